@@ -2,17 +2,35 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"ticker-forge/internal/cli"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		exportMain(os.Args[2:])
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "snapshot" {
+		snapshotMain(os.Args[2:])
+		return
+	}
+
 	mode := flag.String("mode", "tui", "tui|serve")
 	port := flag.String("port", "8080", "port to listen on")
 	symbol := flag.String("symbol", "AAPL", "default ticker")
 	rng := flag.String("range", "1d", "default range (1d,5d,1mo...)")
 	interval := flag.String("interval", "1m", "default interval (1m,5m,15m...)")
+	themeName := flag.String("theme", "", "color theme: dark|light|high-contrast|colorblind or one defined in config.yaml")
+	graphics := flag.String("graphics", "auto", "TUI chart images: auto|sixel|kitty|none")
+	annotations := flag.String("annotations", "", "annotations file or http(s) API URL (default annotations.yaml in the config dir)")
+	fresh := flag.Bool("fresh", false, "start the TUI from -symbol/-range/-interval instead of the tabs saved when it last quit")
+	mouse := flag.String("mouse", "", "TUI mouse support: on|off (default: config.yaml's mouse setting, which is on unless set false)")
+	logLevel := flag.String("log-level", "info", "debug|info|warn|error")
+	logFile := flag.String("log-file", "", "log file (default: ticker-forge.log in the config dir for the TUI, stderr when serving)")
 	flag.Parse()
 
 	opts := cli.Options{
@@ -20,7 +38,16 @@ func main() {
 		DefaultSymbol:   *symbol,
 		DefaultRange:    *rng,
 		DefaultInterval: *interval,
+		Theme:           *themeName,
+		Graphics:        *graphics,
+		Annotations:     *annotations,
+		Fresh:           *fresh,
+		LogLevel:        *logLevel,
+		LogFile:         *logFile,
+		Mouse:           *mouse,
+		SetFlags:        map[string]bool{},
 	}
+	flag.Visit(func(f *flag.Flag) { opts.SetFlags[f.Name] = true })
 	switch *mode {
 	case "serve":
 		opts.Mode = cli.ModeServe
@@ -28,7 +55,56 @@ func main() {
 		opts.Mode = cli.ModeTUI
 	}
 
+	// not log.Fatal: Run routes the log package to its own log, which may
+	// be a file
 	if err := cli.Run(opts); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// exportMain runs "ticker-forge export [flags]", which writes one chart as
+// a PNG or SVG file without starting the TUI or server.
+func exportMain(args []string) {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	opts := cli.ExportOptions{}
+	fs.StringVar(&opts.Symbol, "symbol", "AAPL", "ticker")
+	fs.StringVar(&opts.Symbols, "symbols", "", "comma-separated tickers to compare instead (AAPL,MSFT,QQQ)")
+	fs.StringVar(&opts.Range, "range", "1d", "range (1d,5d,1mo...)")
+	fs.StringVar(&opts.Interval, "interval", "1m", "interval (1m,5m,15m...)")
+	fs.StringVar(&opts.View, "view", "candles", "line|candles|heikin-ashi|ohlc|renko|pnf|kagi")
+	fs.StringVar(&opts.Scale, "scale", "linear", "linear|log|percent")
+	fs.StringVar(&opts.Studies, "studies", "", "overlays, e.g. sma:20,ema:50,bb:20")
+	fs.BoolVar(&opts.Volume, "volume", false, "add a volume panel")
+	fs.IntVar(&opts.Width, "width", 1200, "image width in pixels")
+	fs.IntVar(&opts.Height, "height", 600, "image height in pixels")
+	fs.StringVar(&opts.Theme, "theme", "", "color theme")
+	fs.StringVar(&opts.Output, "o", "", "output file, .png or .svg (default SYMBOL.png, or AAPL-MSFT.png for -symbols; - for stdout)")
+	fs.StringVar(&opts.Format, "format", "", "png|svg (default: from the -o extension)")
+	fs.Parse(args)
+
+	if err := cli.Export(opts); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// snapshotMain runs "ticker-forge snapshot [flags]", which prints one
+// chart as text to stdout without the TUI's alternate screen, for cron
+// and CI. It exits 1 when the fetch fails.
+func snapshotMain(args []string) {
+	fs := flag.NewFlagSet("snapshot", flag.ExitOnError)
+	opts := cli.SnapshotOptions{}
+	fs.StringVar(&opts.Symbol, "symbol", "AAPL", "ticker")
+	fs.StringVar(&opts.Range, "range", "1d", "range (1d,5d,1mo...)")
+	fs.StringVar(&opts.Interval, "interval", "1m", "interval (1m,5m,15m...)")
+	fs.StringVar(&opts.View, "view", "candles", "line|candles")
+	fs.IntVar(&opts.Width, "width", 100, "width in columns (at least 65 for candles, 55 for line)")
+	fs.IntVar(&opts.Height, "height", 30, "height in rows (at least 20 for candles, 18 for line)")
+	fs.StringVar(&opts.Theme, "theme", "", "color theme")
+	fs.StringVar(&opts.Color, "color", "auto", "auto|always|256|never (auto: as stdout supports; always: 24-bit)")
+	fs.Parse(args)
+
+	if err := cli.Snapshot(opts); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,122 @@
+// Package annot loads chart annotations (fills, earnings dates, dividends,
+// notes) from the local annotations file or from an HTTP API, and appends
+// to the local file.
+package annot
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ticker-forge/internal/cfg"
+	"ticker-forge/internal/chart"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath returns the local annotations file,
+// annotations.yaml next to config.yaml.
+func DefaultPath() (string, error) {
+	dir, err := cfg.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "annotations.yaml"), nil
+}
+
+// IsURL reports whether src names an API rather than a file.
+func IsURL(src string) bool {
+	return strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://")
+}
+
+// Load reads the annotations at src: an http(s) URL answering with a JSON
+// array, or a YAML (or JSON) file holding a list. An empty src is
+// DefaultPath; a missing file yields no annotations.
+func Load(src string) ([]chart.Annotation, error) {
+	if IsURL(src) {
+		return fetch(src)
+	}
+	if src == "" {
+		var err error
+		if src, err = DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	b, err := os.ReadFile(src)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parse(src, b)
+}
+
+func parse(src string, b []byte) ([]chart.Annotation, error) {
+	var notes []chart.Annotation
+	if err := yaml.Unmarshal(b, &notes); err != nil {
+		return nil, fmt.Errorf("%s: %w", src, err)
+	}
+	for i, a := range notes {
+		kind, ok := chart.ParseAnnotationKind(string(a.Kind))
+		if !ok {
+			return nil, fmt.Errorf("%s: annotation %d: unknown kind %q", src, i+1, a.Kind)
+		}
+		if a.Time.IsZero() {
+			return nil, fmt.Errorf("%s: annotation %d: missing time", src, i+1)
+		}
+		notes[i].Kind = kind
+		notes[i].Symbol = strings.ToUpper(a.Symbol)
+	}
+	return notes, nil
+}
+
+var client = &http.Client{Timeout: 10 * time.Second}
+
+func fetch(url string) ([]chart.Annotation, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return nil, err
+	}
+	return parse(url, b) // JSON is YAML
+}
+
+// Add appends a to the annotations file at path (DefaultPath when empty),
+// creating it and its directory as needed.
+func Add(path string, a chart.Annotation) error {
+	if IsURL(path) {
+		return fmt.Errorf("annotations come from %s; add them there", path)
+	}
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return err
+		}
+	}
+	notes, err := Load(path)
+	if err != nil {
+		return err
+	}
+	b, err := yaml.Marshal(append(notes, a))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,113 @@
+// Package cfg loads the user's config file
+// (~/.config/tickerforge/config.yaml, %APPDATA%\tickerforge on Windows).
+package cfg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"ticker-forge/internal/theme"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config mirrors config.yaml. Every field is optional.
+type Config struct {
+	// Theme names a built-in theme or one defined under Themes.
+	Theme  string                 `yaml:"theme,omitempty"`
+	Themes map[string]theme.Theme `yaml:"themes,omitempty"`
+
+	// Layout arranges the TUI's panes; nil uses the built-in layout. The
+	// TUI saves it here whenever it changes.
+	Layout *Layout `yaml:"layout,omitempty"`
+
+	// Keymap picks the TUI's key preset: default, vim or emacs. Keys then
+	// rebinds single actions, an action name (? in the TUI lists them) to
+	// its keys, e.g. refresh: [r, f5]; a sequence separates its presses
+	// with spaces, as in "ctrl+x o".
+	Keymap string              `yaml:"keymap,omitempty"`
+	Keys   map[string][]string `yaml:"keys,omitempty"`
+
+	// Mouse turns the TUI's mouse support on or off; nil means on. With it
+	// on, the terminal's own text selection usually needs shift held.
+	Mouse *bool `yaml:"mouse,omitempty"`
+}
+
+// Layout is one TUI pane, or a split holding several. A split lays its
+// Children out side by side (Split "row") or stacked ("column"); a pane
+// names what it shows in Pane. Size is its share of the parent relative
+// to its siblings' sizes.
+type Layout struct {
+	Split    string   `yaml:"split,omitempty"`
+	Pane     string   `yaml:"pane,omitempty"`
+	Size     int      `yaml:"size,omitempty"`
+	Children []Layout `yaml:"children,omitempty"`
+}
+
+// Dir returns the directory holding config.yaml.
+func Dir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "tickerforge"), nil
+}
+
+// Path returns the location of config.yaml.
+func Path() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// Load reads config.yaml; a missing file yields an empty Config.
+func Load() (Config, error) {
+	path, err := Path()
+	if err != nil {
+		return Config{}, err
+	}
+	return LoadFile(path)
+}
+
+// LoadFile reads the config at path; a missing file yields an empty Config.
+func LoadFile(path string) (Config, error) {
+	var c Config
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := yaml.Unmarshal(b, &c); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Update loads config.yaml, lets edit change it and writes it back,
+// creating its directory. Comments in the file are not kept.
+func Update(edit func(*Config)) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	c, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	edit(&c)
+	b, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
@@ -0,0 +1,166 @@
+package chart
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AnnotationKind classifies an Annotation; it picks the marker drawn.
+type AnnotationKind string
+
+const (
+	KindBuy      AnnotationKind = "buy"  // a fill that bought
+	KindSell     AnnotationKind = "sell" // a fill that sold
+	KindEarnings AnnotationKind = "earnings"
+	KindDividend AnnotationKind = "dividend"
+	KindNote     AnnotationKind = "note" // anything else
+)
+
+// Annotation marks an event on the chart of Symbol (every symbol when
+// empty): a fill, an earnings date, a dividend or a free-form note. Price
+// is where it happened, 0 when it has no price of its own.
+type Annotation struct {
+	Symbol string         `yaml:"symbol,omitempty" json:"symbol,omitempty"`
+	Time   time.Time      `yaml:"time" json:"time"`
+	Price  float64        `yaml:"price,omitempty" json:"price,omitempty"`
+	Kind   AnnotationKind `yaml:"kind" json:"kind"`
+	Label  string         `yaml:"label,omitempty" json:"label,omitempty"`
+}
+
+// ParseAnnotationKind maps a kind name (plus "fill-buy"/"b", "s",
+// "earning" and "div") to its AnnotationKind.
+func ParseAnnotationKind(s string) (AnnotationKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "buy", "b", "fill-buy":
+		return KindBuy, true
+	case "sell", "s", "fill-sell":
+		return KindSell, true
+	case "earnings", "earning":
+		return KindEarnings, true
+	case "dividend", "div":
+		return KindDividend, true
+	case "note", "":
+		return KindNote, true
+	}
+	return KindNote, false
+}
+
+// glyph is the character-cell marker for k, its ink and whether it sits
+// above the bar (rather than below it).
+func (k AnnotationKind) glyph() (rune, ink, bool) {
+	switch k {
+	case KindBuy:
+		return '▲', inkUp, false
+	case KindSell:
+		return '▼', inkDown, true
+	case KindEarnings:
+		return 'E', inkCursor, true
+	case KindDividend:
+		return 'D', inkCursor, false
+	}
+	return '*', inkCursor, true
+}
+
+// String is the label used in captions and tooltips: the Label, or the
+// kind and price when there is none.
+func (a Annotation) String() string {
+	if a.Label != "" {
+		return a.Label
+	}
+	if a.Price != 0 {
+		return string(a.Kind) + " @ " + strconv.FormatFloat(a.Price, 'f', 2, 64)
+	}
+	return string(a.Kind)
+}
+
+// AnnotationsFor returns the annotations that apply to symbol, ordered by
+// time.
+func AnnotationsFor(all []Annotation, symbol string) []Annotation {
+	var out []Annotation
+	for _, a := range all {
+		if a.Symbol == "" || strings.EqualFold(a.Symbol, symbol) {
+			out = append(out, a)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b Annotation) int { return a.Time.Compare(b.Time) })
+	return out
+}
+
+// BarAt returns the index of the bar of ticks that t falls in: the last
+// bar starting at or before t. Times before the first bar, or a bar's
+// length or more after the last one, fall in none.
+func BarAt(ticks []Tick, t time.Time) (int, bool) {
+	if len(ticks) == 0 || t.Before(ticks[0].T) {
+		return 0, false
+	}
+	i, found := slices.BinarySearchFunc(ticks, t, func(k Tick, t time.Time) int { return k.T.Compare(t) })
+	if !found {
+		i--
+	}
+	if last := len(ticks) - 1; i == last && t.After(ticks[last].T) {
+		// a lone bar has no length to go by
+		if last == 0 || t.Sub(ticks[last].T) >= ticks[last].T.Sub(ticks[last-1].T) {
+			return 0, false
+		}
+	}
+	return i, true
+}
+
+// AnnotationsAt returns the annotations of notes that fall in bar i.
+func AnnotationsAt(notes []Annotation, ticks []Tick, i int) []Annotation {
+	var out []Annotation
+	for _, a := range notes {
+		if j, ok := BarAt(ticks, a.Time); ok && j == i {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// mark is an annotation or pattern placed on a bar, ready to draw: bar is
+// the index into the ticks passed to Render, hi/lo the extent it clears
+// and glyph, ink and above what goes where.
+type mark struct {
+	bar    int
+	hi, lo float64
+	glyph  rune
+	ink    ink
+	above  bool
+}
+
+// appendMarks places notes on the bars of ticks; line is set for the line
+// view, where markers clear the close rather than the high and low.
+func appendMarks(out []mark, notes []Annotation, ticks []Tick, line bool) []mark {
+	for _, a := range notes {
+		i, ok := BarAt(ticks, a.Time)
+		if !ok {
+			continue
+		}
+		hi, lo := ticks[i].H, ticks[i].L
+		if line {
+			hi, lo = ticks[i].C, ticks[i].C
+		}
+		r, k, above := a.Kind.glyph()
+		out = append(out, mark{bar: i, hi: hi, lo: lo, glyph: r, ink: k, above: above})
+	}
+	return out
+}
+
+// drawMarks puts the markers one row above or below their bar, which sits
+// in column (bar-drop)*step. Markers that would leave the canvas sit on
+// its top or bottom row instead.
+func drawMarks(c *canvas, ax yAxis, marks []mark, drop, step int) {
+	for _, m := range marks {
+		x := (m.bar - drop) * step
+		if x < 0 || x >= c.w {
+			continue
+		}
+		y := ax.row(m.lo) + 1
+		if m.above {
+			y = ax.row(m.hi) - 1
+		}
+		c.set(x, min(max(y, 0), c.h-1), m.glyph, m.ink)
+	}
+}
@@ -0,0 +1,72 @@
+package chart
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBarAt(t *testing.T) {
+	three := []Tick{bar(0, 1, 1, 1, 1, 0), bar(1, 1, 1, 1, 1, 0), bar(2, 1, 1, 1, 1, 0)}
+	at := func(d time.Duration) time.Time { return goldenT0.Add(d) }
+	cases := []struct {
+		name  string
+		ticks []Tick
+		t     time.Time
+		bar   int
+		ok    bool
+	}{
+		{"no bars", nil, at(0), 0, false},
+		{"before the first bar", three, at(-time.Second), 0, false},
+		{"on a bar", three, at(time.Minute), 1, true},
+		{"inside a bar", three, at(90 * time.Second), 1, true},
+		{"inside the last bar", three, at(150 * time.Second), 2, true},
+		{"a bar's length after the last", three, at(3 * time.Minute), 0, false},
+		{"on a lone bar", three[:1], at(0), 0, true},
+		{"after a lone bar", three[:1], at(time.Second), 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			i, ok := BarAt(c.ticks, c.t)
+			if i != c.bar || ok != c.ok {
+				t.Errorf("BarAt = %d, %v; want %d, %v", i, ok, c.bar, c.ok)
+			}
+		})
+	}
+}
+
+func TestAppendMarks(t *testing.T) {
+	ticks := []Tick{bar(0, 100, 102, 98, 101, 0), bar(1, 101, 105, 99, 104, 0)}
+	notes := []Annotation{
+		{Time: goldenT0.Add(-time.Minute), Kind: KindNote}, // before the bars
+		{Time: goldenT0, Kind: KindBuy},
+		{Time: goldenT0.Add(time.Minute), Kind: KindSell},
+		{Time: goldenT0.Add(time.Hour), Kind: KindNote}, // after them
+	}
+	cases := []struct {
+		name string
+		line bool
+		want []mark
+	}{
+		{"candles clear the range", false, []mark{
+			{bar: 0, hi: 102, lo: 98, glyph: '▲', ink: inkUp},
+			{bar: 1, hi: 105, lo: 99, glyph: '▼', ink: inkDown, above: true},
+		}},
+		{"line clears the close", true, []mark{
+			{bar: 0, hi: 101, lo: 101, glyph: '▲', ink: inkUp},
+			{bar: 1, hi: 104, lo: 104, glyph: '▼', ink: inkDown, above: true},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := appendMarks(nil, notes, ticks, c.line)
+			if len(got) != len(c.want) {
+				t.Fatalf("got %d marks, want %d: %+v", len(got), len(c.want), got)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("mark %d = %+v, want %+v", i, got[i], c.want[i])
+				}
+			}
+		})
+	}
+}
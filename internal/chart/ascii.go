@@ -0,0 +1,564 @@
+package chart
+
+import (
+	"strings"
+	"sync"
+
+	"ticker-forge/internal/theme"
+)
+
+// ASCIIChart describes one character-cell chart: which view to draw, the
+// terminal area it fills and the text placed around it.
+type ASCIIChart struct {
+	View    ViewMode
+	Scale   Scale
+	Width   int
+	Height  int
+	Header  string
+	Caption string
+	Footer  string
+
+	// Cursor is the index (into the ticks passed to Render) of the bar
+	// under the crosshair; negative hides it. Only the views that give
+	// every bar its own column (line, candles, heikin-ashi, ohlc) draw it.
+	Cursor int
+
+	// Box is the Renko/Point-and-Figure box size and the Kagi reversal
+	// amount; 0 picks AutoBoxSize. Reversal is the Point-and-Figure
+	// reversal in boxes; 0 means 3.
+	Box      float64
+	Reversal int
+
+	// Annotations are drawn as markers above or below the bars they fall
+	// in, by the views that give every bar its own column.
+	Annotations []Annotation
+
+	// Drawings are the user's levels, trendlines and retracements, drawn
+	// by the same views as Annotations.
+	Drawings []Drawing
+
+	// Patterns are candlestick patterns (see FindPatterns) marked on the
+	// bars they complete on by the candles, heikin-ashi and ohlc views;
+	// Annotations win where both want the same cell.
+	Patterns []PatternMatch
+
+	// Levels (pivot points) and Zones (support and resistance) are drawn
+	// as lines and bands across the line, candles, heikin-ashi and ohlc
+	// views.
+	Levels []Level
+	Zones  []Zone
+
+	// Profile adds a volume or TPO profile of the visible bars to the
+	// right of the candles, heikin-ashi and ohlc views.
+	Profile ProfileMode
+
+	// Palette colors the chart; nil uses the default theme for the
+	// terminal on stdout (no color at all under NO_COLOR).
+	Palette *theme.Palette
+}
+
+func (a ASCIIChart) palette() *theme.Palette {
+	if a.Palette != nil {
+		return a.Palette
+	}
+	return defaultPalette()
+}
+
+// Capacity reports how many bars the chart can show side by side.
+func (a ASCIIChart) Capacity() int {
+	if a.View == ViewLine {
+		w, _ := lineSize(a.Width, a.Height)
+		return w
+	}
+	w, _, _ := a.barPlot()
+	switch a.View {
+	case ViewPointFigure, ViewKagi:
+		return w / 2
+	}
+	return w
+}
+
+// MinSize returns the smallest width and height the chart is drawn at; a
+// smaller Width or Height is drawn at these anyway.
+func (a ASCIIChart) MinSize() (width, height int) {
+	w, h := plotSize(1, 1)
+	if a.View == ViewLine {
+		w, h = lineSize(1, 1)
+	}
+	return w + 4 + axisWidth, h + 8
+}
+
+// PlotRows returns the line of Render's output (RenderCompare's when
+// compare is set) where the plot starts and how many lines it takes. With
+// BarAt it maps mouse positions to bars.
+func (a ASCIIChart) PlotRows(compare bool) (top, rows int) {
+	top = strings.Count(a.Header, "\n") + strings.Count(a.Caption, "\n") + 2
+	switch {
+	case compare:
+		_, rows = lineSize(a.Width, a.Height-1)
+		top++ // the legend
+	case a.View == ViewLine:
+		_, rows = lineSize(a.Width, a.Height)
+	default:
+		_, rows = plotSize(a.Width, a.Height)
+	}
+	return top, rows
+}
+
+// BarAt returns which of the n ticks passed to Render is drawn at column
+// x of a plot row (the y-axis labels included), or -1 when none is or
+// the view doesn't give every bar its own column. It maps mouse
+// positions to bars.
+func (a ASCIIChart) BarAt(x, n int) int {
+	var w, step int
+	switch a.View {
+	case ViewLine:
+		w, _ = lineSize(a.Width, a.Height)
+	case ViewCandles, ViewHeikinAshi, ViewOHLC:
+		w, _, _ = a.barPlot()
+	default:
+		return -1
+	}
+	drop := max(n-w, 0)
+	if n -= drop; n <= 0 {
+		return -1
+	}
+	step = 1
+	if a.View == ViewLine {
+		step = max(1, w/n)
+	}
+	x -= axisWidth
+	if x < 0 {
+		return -1
+	}
+	i := (x + step/2) / step
+	if i >= n {
+		return -1
+	}
+	return drop + i
+}
+
+// barPlot is plotSize less the columns taken by the profile, whose width
+// it also returns (0 when there is none).
+func (a ASCIIChart) barPlot() (w, h, profile int) {
+	w, h = plotSize(a.Width, a.Height)
+	switch a.View {
+	case ViewCandles, ViewHeikinAshi, ViewOHLC:
+		if a.Profile != ProfileNone {
+			profile = profileWidth(w)
+			w = max(w-profile-1, 1)
+		}
+	}
+	return w, h, profile
+}
+
+// Render draws ticks in the configured view. When there are more bars than
+// Capacity, only the most recent ones are drawn.
+func (a ASCIIChart) Render(ticks []Tick) string {
+	r := rendererPool.Get().(*Renderer)
+	defer rendererPool.Put(r)
+	return r.Render(a, ticks)
+}
+
+// Renderer draws ASCIICharts into a canvas and output buffer it keeps
+// between calls, so redrawing on every refresh or crosshair move costs no
+// allocations beyond the returned string (the Renko, Point-and-Figure and
+// Kagi transforms still build their bricks, columns and legs). The zero
+// value is ready to use; a Renderer must not be used concurrently.
+type Renderer struct {
+	c      canvas
+	out    []byte
+	closes []float64
+	ticks  []Tick
+	lines  [][]float64
+	marks  []mark
+}
+
+// rendererPool backs ASCIIChart.Render and the Render*ASCII helpers.
+var rendererPool = sync.Pool{New: func() any { return new(Renderer) }}
+
+// Render is ASCIIChart.Render reusing r's buffers.
+func (r *Renderer) Render(a ASCIIChart, ticks []Tick) string {
+	r.out, r.marks = r.out[:0], r.marks[:0]
+	switch a.View {
+	case ViewLine:
+		r.closes = r.closes[:0]
+		for _, k := range ticks {
+			r.closes = append(r.closes, k.C)
+		}
+		r.marks = appendMarks(r.marks, a.Annotations, ticks, true)
+		r.line(a, r.closes, ticks)
+	case ViewHeikinAshi:
+		r.ticks = appendHeikinAshi(r.ticks[:0], ticks)
+		r.marks = appendPatternMarks(r.marks, a.Patterns, r.ticks)
+		r.marks = appendMarks(r.marks, a.Annotations, r.ticks, false)
+		r.candles(a, r.ticks)
+	case ViewOHLC:
+		r.marks = appendPatternMarks(r.marks, a.Patterns, ticks)
+		r.marks = appendMarks(r.marks, a.Annotations, ticks, false)
+		r.ohlc(a, ticks)
+	case ViewRenko:
+		r.renko(a, ticks)
+	case ViewPointFigure:
+		r.pointFigure(a, ticks)
+	case ViewKagi:
+		r.kagi(a, ticks)
+	default:
+		r.marks = appendPatternMarks(r.marks, a.Patterns, ticks)
+		r.marks = appendMarks(r.marks, a.Annotations, ticks, false)
+		r.candles(a, ticks)
+	}
+	return string(r.out)
+}
+
+// clip keeps the newest n of total bars and shifts the cursor to match.
+func (a *ASCIIChart) clip(total, n int) int {
+	if total <= n {
+		return 0
+	}
+	drop := total - n
+	a.Cursor -= drop
+	return drop
+}
+
+func (a ASCIIChart) box(ticks []Tick) float64 {
+	if a.Box > 0 {
+		return a.Box
+	}
+	return AutoBoxSize(ticks)
+}
+
+// crosshair fills the empty cells of column x with a dotted guide.
+func crosshair(c *canvas, x int) {
+	if x < 0 || x >= c.w {
+		return
+	}
+	for y := 0; y < c.h; y++ {
+		if c.runes[y*c.w+x] == ' ' {
+			c.set(x, y, '┊', inkCursor)
+		}
+	}
+}
+
+// lineSize returns the plot area of the line view; the y-axis labels are
+// carved out of width on top of the usual margin.
+func lineSize(width, height int) (int, int) {
+	if width <= 0 {
+		width = 100
+	}
+	if height <= 0 {
+		height = 30
+	}
+	return max(40, width-4-axisWidth), max(10, height-8)
+}
+
+// line draws closes; ticks, when not nil, are the bars they close and
+// place the drawings.
+func (r *Renderer) line(a ASCIIChart, closes []float64, ticks []Tick) {
+	if len(closes) == 0 {
+		r.placeholder(a, "no data")
+		return
+	}
+	chartW, chartH := lineSize(a.Width, a.Height)
+	drop := a.clip(len(closes), chartW)
+	closes = closes[drop:]
+
+	lo, hi := closes[0], closes[0]
+	for _, v := range closes {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	ax := newYAxis(lo, hi, chartH, a.Scale, closes[0])
+
+	// spread the points out when there are fewer than columns
+	step := max(1, chartW/len(closes))
+	r.c.reset((len(closes)-1)*step+1, chartH)
+	plotLine(&r.c, ax, closes, step, inkUp)
+	if ticks != nil {
+		drawDrawings(&r.c, ax, a.Drawings, ticks[drop:], step)
+	}
+	drawLevels(&r.c, ax, a.Levels, a.Zones, r.c.w, closes[len(closes)-1])
+	if a.Cursor >= 0 && a.Cursor < len(closes) {
+		crosshair(&r.c, a.Cursor*step)
+	}
+	drawMarks(&r.c, ax, r.marks, drop, step)
+	r.frame(a, ax)
+}
+
+// plotLine draws values as a stepped line, one point every step columns.
+func plotLine(c *canvas, ax yAxis, values []float64, step int, k ink) {
+	prev := ax.row(values[0])
+	c.set(0, prev, '─', k)
+	for i := 1; i < len(values); i++ {
+		x, y := i*step, ax.row(values[i])
+		for fill := x - step + 1; fill < x; fill++ {
+			c.set(fill, prev, '─', k)
+		}
+		switch {
+		case y == prev:
+			c.set(x, y, '─', k)
+		case y > prev: // falling: row numbers grow downward
+			c.vline(x, prev, y, '│', k)
+			c.set(x, prev, '╮', k)
+			c.set(x, y, '╰', k)
+		default:
+			c.vline(x, y, prev, '│', k)
+			c.set(x, prev, '╯', k)
+			c.set(x, y, '╭', k)
+		}
+		prev = y
+	}
+}
+
+// RenderCompare draws several series (see CompareSeries) as colored lines
+// on one axis labelled in % change from the first visible bar, with a
+// legend under the caption. Each series is re-indexed at the left edge so
+// zooming and panning compare performance over the visible window.
+func (a ASCIIChart) RenderCompare(series []Series) string {
+	r := rendererPool.Get().(*Renderer)
+	defer rendererPool.Put(r)
+	return r.RenderCompare(a, series)
+}
+
+// RenderCompare is ASCIIChart.RenderCompare reusing r's buffers.
+func (r *Renderer) RenderCompare(a ASCIIChart, series []Series) string {
+	r.out = r.out[:0]
+	r.compare(a, series)
+	return string(r.out)
+}
+
+func (r *Renderer) compare(a ASCIIChart, series []Series) {
+	n := 0
+	for i, s := range series {
+		if i == 0 || len(s.Ticks) < n {
+			n = len(s.Ticks)
+		}
+	}
+	if n == 0 {
+		r.placeholder(a, "no data")
+		return
+	}
+	chartW, chartH := lineSize(a.Width, a.Height-1) // one row for the legend
+	drop := a.clip(n, chartW)
+
+	for len(r.lines) < len(series) {
+		r.lines = append(r.lines, nil)
+	}
+	lines := r.lines[:len(series)]
+	lo, hi := 100.0, 100.0
+	for i, s := range series {
+		// indexed to 100 at the first visible bar, as indexTo100 does
+		visible := s.Ticks[len(s.Ticks)-n+drop:]
+		f := 1.0
+		if visible[0].C != 0 {
+			f = 100 / visible[0].C
+		}
+		lines[i] = lines[i][:0]
+		for _, k := range visible {
+			v := k.C * f
+			lines[i] = append(lines[i], v)
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	ax := makeAxis(lo, hi, chartH, a.Scale == ScaleLog, 100)
+
+	step := max(1, chartW/(n-drop))
+	r.c.reset((n-drop-1)*step+1, chartH)
+	for i, l := range lines {
+		plotLine(&r.c, ax, l, step, seriesInk(i))
+	}
+	if a.Cursor >= 0 && a.Cursor < n-drop {
+		crosshair(&r.c, a.Cursor*step)
+	}
+
+	// the legend is an extra caption line
+	pal := a.palette()
+	r.head(a)
+	for i, s := range series {
+		l := lines[i]
+		if i > 0 {
+			r.out = append(r.out, "   "...)
+		}
+		seq := seriesInk(i).seq(pal)
+		r.out = append(r.out, seq...)
+		r.out = append(r.out, "━━ "...)
+		r.out = append(r.out, s.Name...)
+		r.out = append(r.out, ' ')
+		r.out = appendFixed(r.out, l[len(l)-1]-100, 0, true)
+		r.out = append(r.out, '%')
+		if seq != "" {
+			r.out = append(r.out, pal.Reset...)
+		}
+	}
+	r.out = append(r.out, '\n')
+	r.body(a, ax)
+}
+
+func (r *Renderer) candles(a ASCIIChart, ticks []Tick) {
+	if len(ticks) == 0 {
+		r.placeholder(a, "no data")
+		return
+	}
+	chartW, chartH, pw := a.barPlot()
+
+	// one column per tick (use most recent if narrow)
+	drop := a.clip(len(ticks), chartW)
+	ticks = ticks[drop:]
+
+	lo, hi := ticks[0].L, ticks[0].H
+	for _, k := range ticks {
+		lo, hi = min(lo, k.L), max(hi, k.H)
+	}
+	ax := newYAxis(lo, hi, chartH, a.Scale, ticks[0].C)
+
+	c := &r.c
+	c.reset(len(ticks)+profileGap(pw), chartH)
+	for x, k := range ticks {
+		col := inkDown
+		if k.C >= k.O {
+			col = inkUp
+		}
+		c.vline(x, ax.row(k.H), ax.row(k.L), '│', col) // wick
+		c.vline(x, ax.row(k.O), ax.row(k.C), '█', col) // body
+	}
+	drawDrawings(c, ax, a.Drawings, ticks, 1)
+	drawLevels(c, ax, a.Levels, a.Zones, len(ticks), ticks[len(ticks)-1].C)
+	crosshair(c, a.Cursor)
+	drawMarks(c, ax, r.marks, drop, 1)
+	if pw > 0 {
+		drawProfile(c, ax, ticks, len(ticks)+1, pw, a.Profile)
+	}
+	r.frame(a, ax)
+}
+
+// ohlc draws classic OHLC bars: a vertical high–low range with the
+// open ticked to the left (┤) and the close to the right (├).
+func (r *Renderer) ohlc(a ASCIIChart, ticks []Tick) {
+	if len(ticks) == 0 {
+		r.placeholder(a, "no data")
+		return
+	}
+	chartW, chartH, pw := a.barPlot()
+	drop := a.clip(len(ticks), chartW)
+	ticks = ticks[drop:]
+
+	lo, hi := ticks[0].L, ticks[0].H
+	for _, k := range ticks {
+		lo, hi = min(lo, k.L), max(hi, k.H)
+	}
+	ax := newYAxis(lo, hi, chartH, a.Scale, ticks[0].C)
+
+	c := &r.c
+	c.reset(len(ticks)+profileGap(pw), chartH)
+	for x, k := range ticks {
+		col := inkDown
+		if k.C >= k.O {
+			col = inkUp
+		}
+		c.vline(x, ax.row(k.H), ax.row(k.L), '│', col)
+		yO, yC := ax.row(k.O), ax.row(k.C)
+		if yO == yC {
+			c.set(x, yO, '┼', col)
+			continue
+		}
+		c.set(x, yO, '┤', col)
+		c.set(x, yC, '├', col)
+	}
+	drawDrawings(c, ax, a.Drawings, ticks, 1)
+	drawLevels(c, ax, a.Levels, a.Zones, len(ticks), ticks[len(ticks)-1].C)
+	crosshair(c, a.Cursor)
+	drawMarks(c, ax, r.marks, drop, 1)
+	if pw > 0 {
+		drawProfile(c, ax, ticks, len(ticks)+1, pw, a.Profile)
+	}
+	r.frame(a, ax)
+}
+
+// renko draws one Renko brick per column.
+func (r *Renderer) renko(a ASCIIChart, ticks []Tick) {
+	bricks, truncated := renko(ticks, a.box(ticks))
+	if len(bricks) == 0 {
+		r.placeholder(a, "price has not moved a full box yet")
+		return
+	}
+	if truncated {
+		a.Caption += "   " + renkoNote
+	}
+	a.Cursor, a.Drawings = -1, nil // bricks don't line up with bars
+	r.candles(a, bricks)
+}
+
+// pointFigure draws columns of X (rising) and O (falling) boxes.
+func (r *Renderer) pointFigure(a ASCIIChart, ticks []Tick) {
+	cols := PointFigure(ticks, a.box(ticks), a.Reversal)
+	if len(cols) == 0 {
+		r.placeholder(a, "price has not moved a full box yet")
+		return
+	}
+	chartW, chartH := plotSize(a.Width, a.Height)
+	// two cells per column so X/O columns don't touch
+	if len(cols) > chartW/2 {
+		cols = cols[len(cols)-chartW/2:]
+	}
+
+	lo, hi := cols[0].Low, cols[0].High
+	for _, col := range cols {
+		lo, hi = min(lo, col.Low), max(hi, col.High)
+	}
+	ax := newYAxis(lo, hi, chartH, a.Scale, cols[0].start())
+
+	c := &r.c
+	c.reset(len(cols)*2, chartH)
+	for i, col := range cols {
+		glyph, k := 'O', inkDown
+		if col.Up {
+			glyph, k = 'X', inkUp
+		}
+		c.vline(i*2, ax.row(col.Low), ax.row(col.High), glyph, k)
+	}
+	r.frame(a, ax)
+}
+
+// kagi draws Kagi legs: thick (┃, up color) while yang, thin
+// (│, down color) while yin, joined by horizontal shoulders and waists.
+func (r *Renderer) kagi(a ASCIIChart, ticks []Tick) {
+	legs := Kagi(ticks, a.box(ticks))
+	if len(legs) == 0 {
+		r.placeholder(a, "price has not reversed yet")
+		return
+	}
+	chartW, chartH := plotSize(a.Width, a.Height)
+	if len(legs) > chartW/2 {
+		legs = legs[len(legs)-chartW/2:]
+	}
+
+	lo, hi := legs[0].From, legs[0].From
+	for _, l := range legs {
+		lo, hi = min(lo, l.From, l.To), max(hi, l.From, l.To)
+	}
+	ax := newYAxis(lo, hi, chartH, a.Scale, legs[0].From)
+
+	stroke := func(yang bool) (rune, ink) {
+		if yang {
+			return '┃', inkUp
+		}
+		return '│', inkDown
+	}
+	c := &r.c
+	c.reset(len(legs)*2, chartH)
+	for i, l := range legs {
+		x := i * 2
+		glyph, k := stroke(l.Yang)
+		if !l.Shifted {
+			c.vline(x, ax.row(l.From), ax.row(l.To), glyph, k)
+		} else {
+			c.vline(x, ax.row(l.From), ax.row(l.Shift), glyph, k)
+			glyph, k = stroke(!l.Yang)
+			c.vline(x, ax.row(l.Shift), ax.row(l.To), glyph, k)
+		}
+		if i < len(legs)-1 {
+			_, k = stroke(l.YangAtEnd())
+			c.set(x+1, ax.row(l.To), '─', k)
+		}
+	}
+	r.frame(a, ax)
+}
@@ -0,0 +1,100 @@
+package chart
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"ticker-forge/internal/theme"
+
+	"github.com/muesli/termenv"
+)
+
+// benchSizes are common terminal sizes; the budget is a 120×30 render in
+// under 10ms.
+var benchSizes = [][2]int{{80, 24}, {120, 30}, {200, 50}}
+
+// benchTicks is a trading day of one-minute bars, more than any size shows.
+func benchTicks() []Tick {
+	for _, fx := range fixtures {
+		if fx.name == "trend" {
+			t := fx.ticks()
+			for len(t) < 390 {
+				t = append(t, t...)
+			}
+			return t[:390]
+		}
+	}
+	panic("no trend fixture")
+}
+
+// benchPalette colors every ink, so escape sequences are part of the cost.
+var benchPalette = theme.MustLookup(theme.Default).Palette(termenv.TrueColor)
+
+var benchViews = []ViewMode{ViewLine, ViewCandles, ViewOHLC, ViewHeikinAshi}
+
+// BenchmarkRender measures ASCIIChart.Render, which borrows a pooled
+// Renderer.
+func BenchmarkRender(b *testing.B) {
+	ticks := benchTicks()
+	for _, v := range benchViews {
+		for _, size := range benchSizes {
+			b.Run(fmt.Sprintf("%s_%dx%d", v, size[0], size[1]), func(b *testing.B) {
+				a := ASCIIChart{View: v, Width: size[0], Height: size[1], Cursor: 40, Palette: &benchPalette}
+				b.ReportAllocs()
+				for b.Loop() {
+					a.Render(ticks)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkRenderer measures a long-lived Renderer, as the TUI keeps one,
+// and fails a 120×30 render over the 10ms budget.
+func BenchmarkRenderer(b *testing.B) {
+	ticks := benchTicks()
+	for _, v := range benchViews {
+		for _, size := range benchSizes {
+			b.Run(fmt.Sprintf("%s_%dx%d", v, size[0], size[1]), func(b *testing.B) {
+				var r Renderer
+				a := ASCIIChart{View: v, Width: size[0], Height: size[1], Cursor: 40, Palette: &benchPalette}
+				b.ReportAllocs()
+				for b.Loop() {
+					r.Render(a, ticks)
+				}
+				if d := b.Elapsed() / time.Duration(b.N); size == [2]int{120, 30} && d > 10*time.Millisecond {
+					b.Errorf("%v per 120×30 render, budget 10ms", d)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkRenderCompare(b *testing.B) {
+	ticks := benchTicks()
+	other := make([]Tick, len(ticks))
+	for i, k := range ticks {
+		other[i] = Tick{T: k.T, O: 50, H: 50, L: 50, C: 50 + float64(i%7)}
+	}
+	series := CompareSeries([]Series{{Name: "AAA", Ticks: ticks}, {Name: "BBB", Ticks: other}})
+	var r Renderer
+	a := ASCIIChart{View: ViewLine, Width: 120, Height: 30, Cursor: 40, Palette: &benchPalette}
+	b.ReportAllocs()
+	for b.Loop() {
+		r.RenderCompare(a, series)
+	}
+}
+
+// TestRendererBudget holds a reused Renderer to the output string as its
+// only allocation; BenchmarkRenderer checks the time budget.
+func TestRendererBudget(t *testing.T) {
+	ticks := benchTicks()
+	for _, v := range benchViews {
+		var r Renderer
+		a := ASCIIChart{View: v, Width: 120, Height: 30, Cursor: 40, Palette: &benchPalette}
+		if n := testing.AllocsPerRun(50, func() { r.Render(a, ticks) }); n > 1 {
+			t.Errorf("%s: %v allocations per render, want 1", v, n)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package chart
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"ticker-forge/internal/theme"
+)
+
+// ink is the color class of a canvas cell.
+type ink uint8
+
+const (
+	inkNone ink = iota
+	inkUp
+	inkDown
+	inkCursor
+	inkAccent
+	inkSeries // first compare-series ink; see seriesInk
+)
+
+// seriesInk returns the ink of the i-th compared series.
+func seriesInk(i int) ink {
+	return inkSeries + ink(i%64)
+}
+
+// seq returns the escape sequence that starts cells of ink k under pal.
+func (k ink) seq(pal *theme.Palette) string {
+	switch {
+	case k == inkUp:
+		return pal.Up
+	case k == inkDown:
+		return pal.Down
+	case k == inkCursor:
+		return pal.Cursor
+	case k == inkAccent:
+		return pal.Accent
+	case k >= inkSeries && len(pal.Series) > 0:
+		return pal.Series[int(k-inkSeries)%len(pal.Series)]
+	}
+	return ""
+}
+
+var (
+	defaultPaletteOnce sync.Once
+	defaultPal         theme.Palette
+)
+
+// defaultPalette is the default theme resolved for the terminal on stdout;
+// it is used when an ASCIIChart has no Palette of its own.
+func defaultPalette() *theme.Palette {
+	defaultPaletteOnce.Do(func() {
+		defaultPal = theme.MustLookup(theme.Default).Palette(theme.Detect())
+	})
+	return &defaultPal
+}
+
+// canvas is a fixed grid of runes (rows top→bottom, cols left→right)
+// shared by the character-cell renderers. Its storage is reused from one
+// render to the next; see reset.
+type canvas struct {
+	w, h  int
+	runes []rune
+	inks  []ink
+}
+
+// reset resizes c to w×h and blanks every cell, growing its storage only
+// when the new grid is larger than any before.
+func (c *canvas) reset(w, h int) {
+	n := w * h
+	if cap(c.runes) < n {
+		c.runes, c.inks = make([]rune, n), make([]ink, n)
+	}
+	c.w, c.h = w, h
+	c.runes, c.inks = c.runes[:n], c.inks[:n]
+	for i := range c.runes {
+		c.runes[i] = ' '
+	}
+	clear(c.inks)
+}
+
+func (c *canvas) set(x, y int, r rune, k ink) {
+	if x < 0 || y < 0 || x >= c.w || y >= c.h {
+		return
+	}
+	c.runes[y*c.w+x] = r
+	c.inks[y*c.w+x] = k
+}
+
+// vline draws r from row y0 to y1 (either order) in column x.
+func (c *canvas) vline(x, y0, y1 int, r rune, k ink) {
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	for y := y0; y <= y1; y++ {
+		c.set(x, y, r, k)
+	}
+}
+
+// appendRows appends the canvas rows to dst, each prefixed with its axis
+// label. A run of cells in one color gets a single escape sequence and a
+// single reset; blank cells don't break a run since they show no color.
+func (c *canvas) appendRows(dst []byte, ax yAxis, pal *theme.Palette) []byte {
+	for y := 0; y < c.h; y++ {
+		dst = ax.appendLabel(dst, y)
+		open := "" // escape sequence of the run being written
+		for x := 0; x < c.w; x++ {
+			r, k := c.runes[y*c.w+x], c.inks[y*c.w+x]
+			if r == ' ' && k == inkNone {
+				dst = append(dst, ' ')
+				continue
+			}
+			if seq := k.seq(pal); seq != open {
+				if open != "" {
+					dst = append(dst, pal.Reset...)
+				}
+				dst = append(dst, seq...)
+				open = seq
+			}
+			dst = utf8.AppendRune(dst, r)
+		}
+		if open != "" {
+			dst = append(dst, pal.Reset...)
+		}
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// yAxis maps prices onto canvas rows (row 0 is the top) and labels them.
+type yAxis struct {
+	lo, hi float64 // axis units: log(price) on a log axis, price otherwise
+	rows   int
+	log    bool
+	base   float64 // when non-zero, labels read as % change from base
+}
+
+func newYAxis(lo, hi float64, rows int, s Scale, base float64) yAxis {
+	if s != ScalePercent {
+		base = 0
+	}
+	return makeAxis(lo, hi, rows, s == ScaleLog, base)
+}
+
+func makeAxis(lo, hi float64, rows int, log bool, base float64) yAxis {
+	if log {
+		if lo > 0 {
+			lo, hi = math.Log(lo), math.Log(hi)
+		} else {
+			log = false // non-positive prices can't go on a log axis
+		}
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	return yAxis{lo: lo, hi: hi, rows: rows, log: log, base: base}
+}
+
+func (a yAxis) row(p float64) int {
+	if a.log {
+		p = math.Log(p)
+	}
+	rel := (p - a.lo) / (a.hi - a.lo)
+	y := int(float64(a.rows-1) - rel*float64(a.rows-1))
+	if y < 0 {
+		y = 0
+	}
+	if y >= a.rows {
+		y = a.rows - 1
+	}
+	return y
+}
+
+// contains reports whether price p lies within the axis.
+func (a yAxis) contains(p float64) bool {
+	if a.log {
+		if !(p > 0) {
+			return false
+		}
+		p = math.Log(p)
+	}
+	return p >= a.lo && p <= a.hi
+}
+
+// value is the inverse of row: the price at row y.
+func (a yAxis) value(y int) float64 {
+	v := a.hi
+	if a.rows > 1 {
+		v -= float64(y) * (a.hi - a.lo) / float64(a.rows-1)
+	}
+	if a.log {
+		return math.Exp(v)
+	}
+	return v
+}
+
+// appendLabel appends the axisWidth-wide gutter text for row y.
+func (a yAxis) appendLabel(dst []byte, y int) []byte {
+	v := a.value(y)
+	if a.base != 0 {
+		dst = appendFixed(dst, (v/a.base-1)*100, axisWidth-3, true)
+		return append(dst, "% ┤"...)
+	}
+	dst = appendFixed(dst, v, axisWidth-2, false)
+	return append(dst, " ┤"...)
+}
+
+// appendFixed appends v with two decimals, right-aligned in width
+// columns, as fmt's %*.2f does (%+*.2f when plus is set) but without
+// allocating.
+func appendFixed(dst []byte, v float64, width int, plus bool) []byte {
+	var buf [24]byte
+	num := buf[:0]
+	if plus && !(v < 0) && !math.IsInf(v, 0) && !math.Signbit(v) {
+		num = append(num, '+')
+	}
+	num = strconv.AppendFloat(num, v, 'f', 2, 64)
+	for i := len(num); i < width; i++ {
+		dst = append(dst, ' ')
+	}
+	return append(dst, num...)
+}
+
+// axisWidth is the width of the y-axis gutter ("%9.2f ┤").
+const axisWidth = 11
+
+// plotSize returns the canvas size used by the bar-style renderers for a
+// terminal of width×height.
+func plotSize(width, height int) (int, int) {
+	if width <= 0 {
+		width = 100
+	}
+	if height <= 0 {
+		height = 30
+	}
+	return max(50, width-4-axisWidth), max(12, height-8)
+}
+
+// head appends the header and caption lines that open every chart.
+func (r *Renderer) head(a ASCIIChart) {
+	r.out = append(r.out, a.Header...)
+	r.out = append(r.out, '\n')
+	r.out = append(r.out, a.Caption...)
+	r.out = append(r.out, '\n')
+}
+
+// body appends the labelled canvas and the footer.
+func (r *Renderer) body(a ASCIIChart, ax yAxis) {
+	r.out = r.c.appendRows(r.out, ax, a.palette())
+	r.out = append(r.out, '\n')
+	r.out = append(r.out, a.Footer...)
+	r.out = append(r.out, '\n')
+}
+
+// frame lays out header, caption, labelled canvas and footer the same way
+// for every renderer.
+func (r *Renderer) frame(a ASCIIChart, ax yAxis) {
+	r.head(a)
+	r.body(a, ax)
+}
+
+// placeholder stands in for the canvas when a transform produced nothing
+// to draw (e.g. Renko before price has moved a full box).
+func (r *Renderer) placeholder(a ASCIIChart, msg string) {
+	r.head(a)
+	r.out = append(r.out, "\n("...)
+	r.out = append(r.out, msg...)
+	r.out = append(r.out, ")\n\n"...)
+	r.out = append(r.out, a.Footer...)
+	r.out = append(r.out, '\n')
+}
@@ -0,0 +1,104 @@
+package chart
+
+import (
+	"strings"
+	"time"
+)
+
+// Series is a named run of ticks, e.g. one symbol in a comparison.
+type Series struct {
+	Name  string
+	Ticks []Tick
+}
+
+// Last returns the close of the final tick (0 when empty).
+func (s Series) Last() float64 {
+	if len(s.Ticks) == 0 {
+		return 0
+	}
+	return s.Ticks[len(s.Ticks)-1].C
+}
+
+// CompareSeries aligns several series on the timestamps they all share and
+// indexes each one to 100 at the first shared bar, so that a move from 100
+// to 110 reads as +10% for every symbol. When the series have fewer than
+// two timestamps in common (different exchanges, mismatched intervals)
+// they are aligned by position from their most recent bar instead.
+func CompareSeries(in []Series) []Series {
+	if len(in) == 0 {
+		return nil
+	}
+	shared := map[int64]int{}
+	for _, s := range in {
+		for _, k := range s.Ticks {
+			shared[k.T.Unix()]++
+		}
+	}
+	aligned := make([]Series, len(in))
+	n := -1
+	for i, s := range in {
+		var keep []Tick
+		for _, k := range s.Ticks {
+			if shared[k.T.Unix()] == len(in) {
+				keep = append(keep, k)
+			}
+		}
+		aligned[i] = Series{Name: s.Name, Ticks: keep}
+		if n < 0 || len(keep) < n {
+			n = len(keep)
+		}
+	}
+	if n < 2 {
+		for i, s := range in {
+			aligned[i] = s
+			if i == 0 || len(s.Ticks) < n {
+				n = len(s.Ticks)
+			}
+		}
+	}
+	out := make([]Series, len(aligned))
+	for i, s := range aligned {
+		ticks := s.Ticks[len(s.Ticks)-n:]
+		out[i] = Series{Name: s.Name, Ticks: indexTo100(ticks)}
+	}
+	return out
+}
+
+func indexTo100(ticks []Tick) []Tick {
+	if len(ticks) == 0 || ticks[0].C == 0 {
+		return ticks
+	}
+	f := 100 / ticks[0].C
+	out := make([]Tick, len(ticks))
+	for i, k := range ticks {
+		out[i] = Tick{T: k.T, O: k.O * f, H: k.H * f, L: k.L * f, C: k.C * f, V: k.V}
+	}
+	return out
+}
+
+// ParseSymbols splits a comma/space separated symbol list ("AAPL, msft QQQ")
+// into upper-cased, de-duplicated symbols.
+func ParseSymbols(s string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		f = strings.ToUpper(f)
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// compareTimes returns the x-axis timestamps of aligned series.
+func compareTimes(series []Series) []time.Time {
+	if len(series) == 0 {
+		return nil
+	}
+	ts := make([]time.Time, len(series[0].Ticks))
+	for i, k := range series[0].Ticks {
+		ts[i] = k.T
+	}
+	return ts
+}
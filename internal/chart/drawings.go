@@ -0,0 +1,138 @@
+package chart
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DrawingKind is the tool a Drawing was made with.
+type DrawingKind string
+
+const (
+	DrawLevel DrawingKind = "level" // a horizontal line at From.Price
+	DrawTrend DrawingKind = "trend" // a line through From and To, extended right
+	DrawFib   DrawingKind = "fib"   // Fibonacci retracements of the move From→To
+)
+
+// ParseDrawingKind maps a tool name (plus "hline", "line" and
+// "fibonacci") to its DrawingKind.
+func ParseDrawingKind(s string) (DrawingKind, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "level", "hline", "horizontal":
+		return DrawLevel, true
+	case "trend", "trendline", "line":
+		return DrawTrend, true
+	case "fib", "fibonacci", "retracement":
+		return DrawFib, true
+	}
+	return "", false
+}
+
+// Anchor is a point a drawing is pinned to.
+type Anchor struct {
+	Time  time.Time `yaml:"time" json:"time"`
+	Price float64   `yaml:"price" json:"price"`
+}
+
+// Drawing is a user's mark-up of a chart. Levels only use From.Price.
+type Drawing struct {
+	Kind DrawingKind `yaml:"kind" json:"kind"`
+	From Anchor      `yaml:"from" json:"from"`
+	To   Anchor      `yaml:"to,omitempty" json:"to,omitempty"`
+}
+
+// FibRatios are the retracement levels a DrawFib is drawn at.
+var FibRatios = []float64{0, 0.236, 0.382, 0.5, 0.618, 0.786, 1}
+
+// Validate reports a drawing that can't be drawn.
+func (d Drawing) Validate() error {
+	switch d.Kind {
+	case DrawLevel:
+		return nil
+	case DrawTrend, DrawFib:
+		if d.From.Time.IsZero() || d.To.Time.IsZero() {
+			return fmt.Errorf("a %s needs two points", d.Kind)
+		}
+		if d.Kind == DrawTrend && d.From.Time.Equal(d.To.Time) {
+			return fmt.Errorf("a trendline's points need different times")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown drawing kind %q (want level, trend or fib)", d.Kind)
+}
+
+// String describes the drawing for status lines and lists.
+func (d Drawing) String() string {
+	switch d.Kind {
+	case DrawLevel:
+		return fmt.Sprintf("level %.2f", d.From.Price)
+	case DrawFib:
+		return fmt.Sprintf("fib %.2f→%.2f", d.From.Price, d.To.Price)
+	}
+	return fmt.Sprintf("trend %.2f→%.2f", d.From.Price, d.To.Price)
+}
+
+// FibLevel returns the price of retracement ratio r: r = 0 is the end of
+// the move (To) and r = 1 its start (From).
+func (d Drawing) FibLevel(r float64) float64 {
+	return d.To.Price - (d.To.Price-d.From.Price)*r
+}
+
+// TrendAt returns the trendline's price at t, and false before From,
+// where the line doesn't reach.
+func (d Drawing) TrendAt(t time.Time) (float64, bool) {
+	span := d.To.Time.Sub(d.From.Time)
+	if span == 0 || t.Before(d.From.Time) {
+		return 0, false
+	}
+	f := float64(t.Sub(d.From.Time)) / float64(span)
+	return d.From.Price + (d.To.Price-d.From.Price)*f, true
+}
+
+// drawDrawings overlays ds on the blank cells of c, whose columns hold
+// the bars of ticks (the visible ones) every step columns, so candles stay
+// readable. Lines priced off the axis are left out rather than clamped.
+func drawDrawings(c *canvas, ax yAxis, ds []Drawing, ticks []Tick, step int) {
+	end := min((len(ticks)-1)*step+1, c.w) // columns past it hold a profile
+	hline := func(p float64, r rune, from int) {
+		if !ax.contains(p) {
+			return
+		}
+		y := ax.row(p)
+		for x := max(from, 0); x < end; x++ {
+			if c.runes[y*c.w+x] == ' ' {
+				c.set(x, y, r, inkAccent)
+			}
+		}
+	}
+	// col is the first column at or after t
+	col := func(t time.Time) int {
+		for i, k := range ticks {
+			if !k.T.Before(t) {
+				return i * step
+			}
+		}
+		return end
+	}
+	for _, d := range ds {
+		switch d.Kind {
+		case DrawLevel:
+			hline(d.From.Price, '┄', 0)
+		case DrawFib:
+			for _, r := range FibRatios {
+				hline(d.FibLevel(r), '┈', col(d.From.Time))
+			}
+		case DrawTrend:
+			for i, k := range ticks {
+				p, ok := d.TrendAt(k.T)
+				if !ok || !ax.contains(p) {
+					continue
+				}
+				if x, y := i*step, ax.row(p); c.runes[y*c.w+x] == ' ' {
+					c.set(x, y, '·', inkAccent)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package chart
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestDrawingValidate(t *testing.T) {
+	t1 := goldenT0.Add(time.Hour)
+	cases := []struct {
+		name string
+		d    Drawing
+		ok   bool
+	}{
+		{"level", Drawing{Kind: DrawLevel, From: Anchor{Price: 100}}, true},
+		{"trend", Drawing{Kind: DrawTrend, From: Anchor{Time: goldenT0}, To: Anchor{Time: t1}}, true},
+		{"trend missing a point", Drawing{Kind: DrawTrend, From: Anchor{Time: goldenT0}}, false},
+		{"vertical trend", Drawing{Kind: DrawTrend, From: Anchor{Time: goldenT0}, To: Anchor{Time: goldenT0}}, false},
+		{"fib on one time", Drawing{Kind: DrawFib, From: Anchor{Time: goldenT0}, To: Anchor{Time: goldenT0}}, true},
+		{"unknown kind", Drawing{Kind: "arrow"}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if err := c.d.Validate(); (err == nil) != c.ok {
+				t.Errorf("Validate() = %v, want ok %v", err, c.ok)
+			}
+		})
+	}
+}
+
+func TestFibLevel(t *testing.T) {
+	d := Drawing{Kind: DrawFib, From: Anchor{Price: 100}, To: Anchor{Price: 200}}
+	for _, c := range []struct{ ratio, want float64 }{
+		{0, 200}, {0.5, 150}, {0.618, 138.2}, {1, 100},
+	} {
+		if got := d.FibLevel(c.ratio); !near(got, c.want) {
+			t.Errorf("FibLevel(%g) = %g, want %g", c.ratio, got, c.want)
+		}
+	}
+}
+
+func TestTrendAt(t *testing.T) {
+	d := Drawing{Kind: DrawTrend,
+		From: Anchor{Time: goldenT0, Price: 100},
+		To:   Anchor{Time: goldenT0.Add(10 * time.Minute), Price: 110}}
+	cases := []struct {
+		name  string
+		at    time.Duration
+		price float64
+		ok    bool
+	}{
+		{"before the first point", -time.Minute, 0, false},
+		{"on the first point", 0, 100, true},
+		{"between the points", 5 * time.Minute, 105, true},
+		{"extended past the second", 20 * time.Minute, 120, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			p, ok := d.TrendAt(goldenT0.Add(c.at))
+			if ok != c.ok || !near(p, c.price) {
+				t.Errorf("TrendAt = %g, %v; want %g, %v", p, ok, c.price, c.ok)
+			}
+		})
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
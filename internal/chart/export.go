@@ -0,0 +1,87 @@
+package chart
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image/color"
+	"image/png"
+	"io"
+
+	"ticker-forge/internal/theme"
+)
+
+// Encode writes the chart of ticks to w as "png" or "svg".
+func (a ImageChart) Encode(w io.Writer, format string, ticks []Tick) error {
+	return a.encode(w, format, func(a ImageChart, s surface) { a.paint(s, ticks) })
+}
+
+// EncodeCompare writes the comparison chart of series (see CompareSeries)
+// to w as "png" or "svg".
+func (a ImageChart) EncodeCompare(w io.Writer, format string, series []Series) error {
+	return a.encode(w, format, func(a ImageChart, s surface) { a.paintCompare(s, series) })
+}
+
+func (a ImageChart) encode(w io.Writer, format string, paint func(ImageChart, surface)) error {
+	a = a.defaults()
+	bg := theme.RGBA(a.Theme.Background)
+	switch format {
+	case "png":
+		r := newRaster(a.Width, a.Height, bg)
+		paint(a, r)
+		return png.Encode(w, r.img)
+	case "svg":
+		bw := bufio.NewWriter(w)
+		s := newSVG(bw, a.Width, a.Height, bg)
+		paint(a, s)
+		s.close()
+		return bw.Flush()
+	}
+	return fmt.Errorf("unknown export format %q (want png or svg)", format)
+}
+
+// svgSurface writes SVG elements as they are drawn. Text uses a monospace
+// font sized so glyphs are about glyphW pixels wide, as in the PNG.
+type svgSurface struct {
+	w io.Writer
+}
+
+func newSVG(w io.Writer, width, height int, bg color.RGBA) *svgSurface {
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(bg))
+	fmt.Fprintln(w, `<g font-family="DejaVu Sans Mono, Menlo, Consolas, monospace" font-size="12" shape-rendering="crispEdges">`)
+	return &svgSurface{w: w}
+}
+
+func (s *svgSurface) close() {
+	fmt.Fprintln(s.w, "</g>\n</svg>")
+}
+
+func (s *svgSurface) rect(x0, y0, x1, y1 int, c color.RGBA) {
+	if x1 < x0 {
+		x0, x1 = x1, x0
+	}
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	fmt.Fprintf(s.w, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`+"\n",
+		x0, y0, x1-x0+1, y1-y0+1, hexColor(c))
+}
+
+func (s *svgSurface) line(x0, y0, x1, y1, width int, c color.RGBA) {
+	// pixel centres, so 1px lines land on a single row/column
+	fmt.Fprintf(s.w, `<line x1="%d.5" y1="%d.5" x2="%d.5" y2="%d.5" stroke="%s" stroke-width="%d" stroke-linecap="square"/>`+"\n",
+		x0, y0, x1, y1, hexColor(c), max(1, width))
+}
+
+func (s *svgSurface) text(x, y int, str string, c color.RGBA) {
+	if str == "" {
+		return
+	}
+	fmt.Fprintf(s.w, `<text x="%d" y="%d" fill="%s">%s</text>`+"\n", x, y, hexColor(c), html.EscapeString(str))
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,68 @@
+package chart
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+// tickBytes is the encoded size of one Tick: O, H, L, C as float64 bits
+// and V.
+const tickBytes = 5 * 8
+
+func encodeTicks(ticks []Tick) []byte {
+	out := make([]byte, 0, len(ticks)*tickBytes)
+	for _, k := range ticks {
+		for _, v := range []float64{k.O, k.H, k.L, k.C} {
+			out = binary.LittleEndian.AppendUint64(out, math.Float64bits(v))
+		}
+		out = binary.LittleEndian.AppendUint64(out, uint64(k.V))
+	}
+	return out
+}
+
+// decodeTicks turns arbitrary bytes into ticks, NaNs, infinities and
+// H < L included; trailing bytes are ignored.
+func decodeTicks(data []byte) []Tick {
+	n := min(len(data)/tickBytes, 300) // more bars only slow the fuzzer down
+	out := make([]Tick, n)
+	for i := range out {
+		f := func(j int) float64 {
+			return math.Float64frombits(binary.LittleEndian.Uint64(data[i*tickBytes+j*8:]))
+		}
+		out[i] = Tick{
+			T: goldenT0.Add(time.Duration(i) * time.Minute),
+			O: f(0), H: f(1), L: f(2), C: f(3),
+			V: int64(binary.LittleEndian.Uint64(data[i*tickBytes+32:])),
+		}
+	}
+	return out
+}
+
+// FuzzRenderers checks that no renderer panics, whatever the ticks and
+// terminal size. Run it with go test ./internal/chart -fuzz FuzzRenderers.
+func FuzzRenderers(f *testing.F) {
+	for _, fx := range fixtures {
+		f.Add(encodeTicks(fx.ticks()), 80, 24)
+	}
+	f.Add(encodeTicks([]Tick{
+		bar(0, 1, 1, 1, 1, 0),
+		bar(1, 1e300, math.Inf(1), -1e300, 1e300, -1),
+		bar(2, math.NaN(), math.NaN(), math.NaN(), math.NaN(), 0),
+		bar(3, -5, -4, -6, -5, math.MaxInt64),
+	}), 0, 0)
+
+	f.Fuzz(func(t *testing.T, data []byte, w, h int) {
+		ticks := decodeTicks(data)
+		w, h = w%400, h%120
+		for _, r := range renderers {
+			r.render(ticks, w, h)
+		}
+		for v := ViewLine; v <= ViewKagi; v++ {
+			ASCIIChart{View: v, Width: w, Height: h, Cursor: w}.Render(ticks)
+			ImageChart{View: v, Width: 160, Height: 90, Cursor: h, Volume: true}.Draw(ticks)
+		}
+		ImageChart{Width: 160, Height: 90}.DrawCompare(CompareSeries([]Series{{Name: "A", Ticks: ticks}, {Name: "B", Ticks: ticks}}))
+	})
+}
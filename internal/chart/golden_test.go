@@ -0,0 +1,235 @@
+package chart
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+func TestMain(m *testing.M) {
+	// Golden files hold plain text: pin the default palette to no color
+	// whatever terminal or CLICOLOR_FORCE the tests run under.
+	defaultPaletteOnce.Do(func() {})
+	os.Exit(m.Run())
+}
+
+// goldenT0 is the time of the first bar of every fixture.
+var goldenT0 = time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+
+func bar(i int, o, h, l, c float64, v int64) Tick {
+	return Tick{T: goldenT0.Add(time.Duration(i) * time.Minute), O: o, H: h, L: l, C: c, V: v}
+}
+
+// fixtures are the series every renderer is checked against.
+var fixtures = []struct {
+	name  string
+	ticks func() []Tick
+}{
+	{"empty", func() []Tick { return nil }},
+	{"single", func() []Tick { return []Tick{bar(0, 100, 101, 99, 100.5, 1000)} }},
+	{"flat", func() []Tick {
+		out := make([]Tick, 60)
+		for i := range out {
+			out[i] = bar(i, 100, 100, 100, 100, 500)
+		}
+		return out
+	}},
+	{"gap", func() []Tick {
+		// a bad print: one bar a hundred times the price around it
+		out := make([]Tick, 60)
+		for i := range out {
+			p := 100 + float64(i%5)
+			if i == 30 {
+				p = 10000
+			}
+			out[i] = bar(i, p-0.5, p+1, p-1, p, int64(100*i))
+		}
+		return out
+	}},
+	{"trend", func() []Tick {
+		out := make([]Tick, 150)
+		p := 100.0
+		for i := range out {
+			o := p
+			p += math.Sin(float64(i)/9)*0.9 + 0.05
+			out[i] = bar(i, o, math.Max(o, p)+0.4, math.Min(o, p)-0.4, p, int64(1000+(i*37)%700))
+		}
+		return out
+	}},
+}
+
+func closesOf(ticks []Tick) []float64 {
+	out := make([]float64, len(ticks))
+	for i, k := range ticks {
+		out[i] = k.C
+	}
+	return out
+}
+
+// goldenSizes are terminal sizes: a roomy 120×30, the usual 80×24 and
+// one below the renderers' minimum plot size.
+var goldenSizes = [][2]int{{120, 30}, {80, 24}, {40, 12}}
+
+// renderers maps a golden-file prefix to a renderer; add new renderers
+// here. Each is checked at sizes, or at 80×24 when sizes is nil.
+var renderers = []struct {
+	name   string
+	render func(ticks []Tick, w, h int) string
+	sizes  [][2]int
+}{
+	{"line", func(t []Tick, w, h int) string {
+		return RenderLineASCII(closesOf(t), w, h, "header", "caption", "footer")
+	}, goldenSizes},
+	{"candles", func(t []Tick, w, h int) string {
+		return RenderCandlesASCII(t, w, h, "header", "caption", "footer")
+	}, goldenSizes},
+	{"heikin-ashi", func(t []Tick, w, h int) string {
+		return RenderHeikinAshiASCII(t, w, h, "header", "caption", "footer")
+	}, nil},
+	{"ohlc", func(t []Tick, w, h int) string {
+		return RenderOHLCBarsASCII(t, w, h, "header", "caption", "footer")
+	}, nil},
+	{"renko", func(t []Tick, w, h int) string {
+		return RenderRenkoASCII(t, 0, w, h, "header", "caption", "footer")
+	}, nil},
+	{"pnf", func(t []Tick, w, h int) string {
+		return RenderPointFigureASCII(t, 0, 3, w, h, "header", "caption", "footer")
+	}, nil},
+	{"kagi", func(t []Tick, w, h int) string {
+		return RenderKagiASCII(t, 0, w, h, "header", "caption", "footer")
+	}, nil},
+	{"line-log", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewLine, Scale: ScaleLog, Width: w, Height: h, Cursor: -1}.Render(t)
+	}, nil},
+	{"candles-percent", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewCandles, Scale: ScalePercent, Width: w, Height: h, Cursor: -1}.Render(t)
+	}, nil},
+	{"candles-color", func(t []Tick, w, h int) string {
+		// runs of one color share an escape sequence
+		return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: len(t) / 2, Palette: &benchPalette}.Render(t)
+	}, nil},
+	{"annotations", func(t []Tick, w, h int) string {
+		// placed from the end so they land on the visible bars
+		at := func(back int) time.Time { return goldenT0.Add(time.Duration(len(t)-back) * time.Minute) }
+		notes := []Annotation{
+			{Time: goldenT0.Add(-time.Minute), Kind: KindNote}, // before the first bar
+			{Time: at(45), Kind: KindBuy, Price: 101},
+			{Time: at(35).Add(30 * time.Second), Kind: KindSell},
+			{Time: at(25), Kind: KindEarnings},
+			{Time: at(15), Kind: KindDividend},
+			{Time: at(5), Kind: KindNote, Label: "note"},
+			{Time: at(-5), Kind: KindNote}, // after the last bar
+		}
+		return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: -1, Annotations: notes}.Render(t)
+	}, nil},
+	{"drawings", func(t []Tick, w, h int) string {
+		if len(t) == 0 {
+			return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: -1}.Render(t)
+		}
+		first, last := t[max(0, len(t)-60)], t[len(t)-1]
+		ds := []Drawing{
+			{Kind: DrawLevel, From: Anchor{Price: first.C}},
+			{Kind: DrawTrend, From: Anchor{Time: first.T, Price: first.L}, To: Anchor{Time: last.T, Price: last.L}},
+			{Kind: DrawFib, From: Anchor{Time: first.T, Price: first.L}, To: Anchor{Time: last.T, Price: last.H}},
+		}
+		return ASCIIChart{View: ViewLine, Width: w, Height: h, Cursor: -1, Drawings: ds}.Render(t)
+	}, nil},
+	{"patterns", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: -1, Patterns: FindPatterns(t)}.Render(t)
+	}, nil},
+	{"volume-profile", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: -1, Profile: ProfileVolume}.Render(t)
+	}, nil},
+	{"tpo-profile", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewOHLC, Width: w, Height: h, Cursor: -1, Profile: ProfileTPO}.Render(t)
+	}, nil},
+	{"levels", func(t []Tick, w, h int) string {
+		var levels []Level
+		if len(t) > 0 {
+			hi, lo := t[0].H, t[0].L
+			for _, k := range t {
+				hi, lo = max(hi, k.H), min(lo, k.L)
+			}
+			levels = Pivots(PivotClassic, hi, lo, t[len(t)/2].C)
+		}
+		return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: -1, Levels: levels, Zones: SRZones(t, MaxZones)}.Render(t)
+	}, nil},
+	{"line-levels", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewLine, Width: w, Height: h, Cursor: -1, Zones: SRZones(t, MaxZones)}.Render(t)
+	}, nil},
+	{"crosshair", func(t []Tick, w, h int) string {
+		return ASCIIChart{View: ViewCandles, Width: w, Height: h, Cursor: len(t) / 2}.Render(t)
+	}, nil},
+	{"compare", func(t []Tick, w, h int) string {
+		other := make([]Tick, len(t))
+		for i, k := range t {
+			other[i] = Tick{T: k.T, O: 50, H: 50, L: 50, C: 50 + float64(i%7)}
+		}
+		series := CompareSeries([]Series{{Name: "AAA", Ticks: t}, {Name: "BBB", Ticks: other}})
+		return ASCIIChart{View: ViewLine, Width: w, Height: h, Cursor: -1}.RenderCompare(series)
+	}, nil},
+	{"spark-blocks", func(t []Tick, w, _ int) string {
+		return Sparkline{Width: w / 4, Color: true}.Render(closesOf(t)) + "\n"
+	}, nil},
+	{"spark-braille", func(t []Tick, w, _ int) string {
+		return Sparkline{Style: SparkBraille, Width: w / 4, Color: true}.Render(closesOf(t)) + "\n"
+	}, nil},
+}
+
+// overlays are renderers drawing a feature over the candles whose own
+// behavior is unit tested; their golden files cover the trend fixture
+// only.
+var overlays = map[string]bool{
+	"annotations":    true,
+	"drawings":       true,
+	"patterns":       true,
+	"levels":         true,
+	"line-levels":    true,
+	"volume-profile": true,
+	"tpo-profile":    true,
+}
+
+func TestGolden(t *testing.T) {
+	for _, r := range renderers {
+		sizes := r.sizes
+		if sizes == nil {
+			sizes = [][2]int{{80, 24}}
+		}
+		for _, fx := range fixtures {
+			if overlays[r.name] && fx.name != "trend" {
+				continue
+			}
+			for _, size := range sizes {
+				name := fmt.Sprintf("%s_%s_%dx%d", r.name, fx.name, size[0], size[1])
+				t.Run(name, func(t *testing.T) {
+					got := r.render(fx.ticks(), size[0], size[1])
+					checkGolden(t, name, got)
+				})
+			}
+		}
+	}
+}
+
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".txt")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test ./internal/chart -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update if the change is intended)\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
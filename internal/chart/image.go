@@ -0,0 +1,588 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+
+	"ticker-forge/internal/chart/studies"
+	"ticker-forge/internal/theme"
+)
+
+// ImageChart describes one pixel chart, the image counterpart of
+// ASCIIChart used for Sixel/Kitty terminal output and PNG/SVG export.
+type ImageChart struct {
+	View   ViewMode
+	Scale  Scale
+	Width  int // pixels; 0 means 960
+	Height int // pixels; 0 means 540
+	Title  string
+
+	// Cursor is the index of the bar under the crosshair; negative hides
+	// it. As with ASCIIChart only per-bar views draw it.
+	Cursor int
+
+	// Box and Reversal are as in ASCIIChart.
+	Box      float64
+	Reversal int
+
+	// Theme colors the chart; the zero value uses theme.Default.
+	Theme theme.Theme
+
+	// Volume adds a volume panel and Studies overlays indicators on the
+	// price; both apply to the per-bar views only.
+	Volume  bool
+	Studies []studies.Spec
+}
+
+// Draw rasterizes ticks in the configured view. When there are more bars
+// than pixel columns, only the most recent ones are drawn.
+func (a ImageChart) Draw(ticks []Tick) *image.RGBA {
+	a = a.defaults()
+	r := newRaster(a.Width, a.Height, theme.RGBA(a.Theme.Background))
+	a.paint(r, ticks)
+	return r.img
+}
+
+// DrawCompare rasterizes several series (see CompareSeries) as colored
+// lines labelled in % change from the first bar, like RenderCompare.
+func (a ImageChart) DrawCompare(series []Series) *image.RGBA {
+	a = a.defaults()
+	r := newRaster(a.Width, a.Height, theme.RGBA(a.Theme.Background))
+	a.paintCompare(r, series)
+	return r.img
+}
+
+func (a ImageChart) defaults() ImageChart {
+	if a.Width <= 0 {
+		a.Width = 960
+	}
+	if a.Height <= 0 {
+		a.Height = 540
+	}
+	if a.Theme.Up == "" {
+		a.Theme = theme.MustLookup(theme.Default)
+	}
+	return a
+}
+
+// perBar reports whether the view draws one slot per input bar.
+func (a ImageChart) perBar() bool {
+	switch a.View {
+	case ViewLine, ViewCandles, ViewHeikinAshi, ViewOHLC:
+		return true
+	}
+	return false
+}
+
+func (a ImageChart) paint(s surface, ticks []Tick) {
+	switch a.View {
+	case ViewLine:
+		a.paintLine(s, ticks)
+	case ViewHeikinAshi:
+		a.paintBars(s, HeikinAshi(ticks), false)
+	case ViewOHLC:
+		a.paintBars(s, ticks, true)
+	case ViewRenko:
+		bricks, truncated := renko(ticks, a.box(ticks))
+		if len(bricks) == 0 {
+			a.message(s, "price has not moved a full box yet")
+			return
+		}
+		if truncated {
+			a.Title += "  " + renkoNote
+		}
+		a.Cursor = -1 // bricks don't line up with bars
+		a.paintBars(s, bricks, false)
+	case ViewPointFigure:
+		a.paintPointFigure(s, ticks)
+	case ViewKagi:
+		a.paintKagi(s, ticks)
+	default:
+		a.paintBars(s, ticks, false)
+	}
+}
+
+func (a ImageChart) box(ticks []Tick) float64 {
+	if a.Box > 0 {
+		return a.Box
+	}
+	return AutoBoxSize(ticks)
+}
+
+// clip keeps the newest n of total bars and shifts the cursor to match.
+func (a *ImageChart) clip(total, n int) int {
+	if total <= n {
+		return 0
+	}
+	drop := total - n
+	a.Cursor -= drop
+	return drop
+}
+
+// percentBase is the value the axis labels are relative to, if any.
+func (a ImageChart) percentBase(v float64) float64 {
+	if a.Scale == ScalePercent {
+		return v
+	}
+	return 0
+}
+
+// Pixel margins around the plot area.
+const (
+	imgPad    = 8
+	imgTitleH = 20
+	imgTimeH  = 18
+)
+
+// plot is the laid-out plot area of an image chart: n equal slots across
+// (bars, columns or legs) and a y axis mapping prices to pixel rows, with
+// an optional volume panel of volH rows underneath.
+type plot struct {
+	s          surface
+	ax         yAxis
+	x0, y0     int
+	w, h       int
+	n          int
+	volY, volH int
+	up, down   color.RGBA
+	fg, grid   color.RGBA
+	accent, bg color.RGBA
+}
+
+// plotWidth is the plot area width before the axis gutter is known; it is
+// what the renderers clip against.
+func (a ImageChart) plotWidth() int {
+	return max(1, a.Width-2*imgPad-textWidth("-00000.00%")-imgPad)
+}
+
+// legendItem is one colored entry on the title row.
+type legendItem struct {
+	label string
+	color color.RGBA
+}
+
+// frameSpec is what layout needs to know about the data.
+type frameSpec struct {
+	lo, hi float64
+	base   float64     // labels read as % change from base when non-zero
+	times  []time.Time // one per slot; its length is the slot count
+	volume bool
+	legend []legendItem
+}
+
+// layout sizes the plot for f, then draws the title and legend, grid,
+// price labels and time labels.
+func (a ImageChart) layout(s surface, f frameSpec) plot {
+	t := a.Theme
+	bg := theme.RGBA(t.Background)
+	fg := theme.RGBA(t.Subtle)
+	p := plot{
+		s: s, n: max(1, len(f.times)),
+		up: theme.RGBA(t.Up), down: theme.RGBA(t.Down),
+		fg: fg, grid: mix(bg, fg, 0.25), accent: theme.RGBA(t.Accent), bg: bg,
+	}
+	top := imgPad
+	if a.Title != "" || len(f.legend) > 0 {
+		s.text(imgPad, imgPad+glyphH-2, a.Title, p.accent)
+		x := imgPad + textWidth(a.Title) + 3*imgPad
+		for _, l := range f.legend {
+			s.rect(x, imgPad+glyphH/2-1, x+13, imgPad+glyphH/2+1, l.color)
+			s.text(x+18, imgPad+glyphH-2, l.label, l.color)
+			x += 18 + textWidth(l.label) + 2*imgPad
+		}
+		top += imgTitleH
+	}
+	p.y0 = top
+	p.h = max(2, a.Height-top-imgTimeH-imgPad)
+	if f.volume {
+		p.volH = p.h / 5
+		p.h -= p.volH + imgPad
+		p.volY = p.y0 + p.h + imgPad
+	}
+	p.ax = makeAxis(f.lo, f.hi, p.h, a.Scale == ScaleLog, f.base)
+
+	gutter := max(textWidth(axisText(p.ax, p.ax.value(0))), textWidth(axisText(p.ax, p.ax.value(p.h-1))))
+	p.x0 = imgPad + gutter + imgPad
+	p.w = max(1, a.Width-p.x0-imgPad)
+
+	const gridLines = 5
+	for i := 0; i < gridLines; i++ {
+		row := i * (p.h - 1) / (gridLines - 1)
+		y := p.y0 + row
+		s.line(p.x0, y, p.x0+p.w-1, y, 1, p.grid)
+		label := axisText(p.ax, p.ax.value(row))
+		s.text(p.x0-imgPad-textWidth(label), y+glyphH/2-2, label, p.fg)
+	}
+
+	if times := f.times; len(times) > 0 {
+		layout := "15:04"
+		if times[len(times)-1].Sub(times[0]) >= 24*time.Hour {
+			layout = "Jan 2 15:04"
+		}
+		labelW := textWidth(layout) + 2*imgPad
+		every := max(1, len(times)*labelW/p.w)
+		bottom := p.y0 + p.h
+		if f.volume {
+			bottom = p.volY + p.volH
+		}
+		for i := 0; i < len(times); i += every {
+			label := times[i].Format(layout)
+			x := p.x(i) - textWidth(label)/2
+			if x < p.x0 || x+textWidth(label) > p.x0+p.w {
+				continue
+			}
+			s.text(x, bottom+imgTimeH-4, label, p.fg)
+		}
+	}
+	return p
+}
+
+// axisText formats a price label, or a % change when the axis has a base.
+func axisText(ax yAxis, v float64) string {
+	if ax.base != 0 {
+		return fmt.Sprintf("%+.2f%%", (v/ax.base-1)*100)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
+// x is the pixel column at the centre of slot i.
+func (p plot) x(i int) int {
+	return p.x0 + (2*i+1)*p.w/(2*p.n)
+}
+
+// y is the pixel row of price v.
+func (p plot) y(v float64) int {
+	return p.y0 + p.ax.row(v)
+}
+
+// slot is the pixel width given to each slot.
+func (p plot) slot() int {
+	return max(1, p.w/p.n)
+}
+
+// crosshair draws a dashed vertical guide through slot i.
+func (p plot) crosshair(i int) {
+	if i < 0 || i >= p.n {
+		return
+	}
+	x := p.x(i)
+	for y := p.y0; y < p.y0+p.h; y += 6 {
+		p.s.line(x, y, x, min(y+2, p.y0+p.h-1), 1, p.fg)
+	}
+}
+
+// volume draws one bar per tick in the volume panel, colored like the
+// candle above it.
+func (p plot) volume(ticks []Tick) {
+	var top int64
+	for _, k := range ticks {
+		top = max(top, k.V)
+	}
+	if p.volH == 0 || top == 0 {
+		return
+	}
+	s := p.s
+	s.line(p.x0, p.volY+p.volH-1, p.x0+p.w-1, p.volY+p.volH-1, 1, p.grid)
+	label := fmt.Sprintf("%d", top)
+	if top >= 1e6 {
+		label = fmt.Sprintf("%.1fM", float64(top)/1e6)
+	} else if top >= 1e3 {
+		label = fmt.Sprintf("%.1fK", float64(top)/1e3)
+	}
+	s.text(p.x0-imgPad-textWidth(label), p.volY+glyphH-2, label, p.fg)
+
+	body := max(1, p.slot()*7/10)
+	for i, k := range ticks {
+		if k.V <= 0 {
+			continue
+		}
+		col := p.down
+		if k.C >= k.O {
+			col = p.up
+		}
+		h := int(float64(k.V) / float64(top) * float64(p.volH-1))
+		left := p.x(i) - body/2
+		s.rect(left, p.volY+p.volH-1-h, left+body-1, p.volY+p.volH-1, mix(p.bg, col, 0.6))
+	}
+}
+
+// studyLines computes a.Studies over the closes of ticks and returns the
+// lines with their colors, and the legend entries for them. Series colors
+// are used from the second on; the first is usually the up color.
+func (a ImageChart) studyLines(ticks []Tick) ([]studies.Line, []color.RGBA, []legendItem) {
+	if len(a.Studies) == 0 || !a.perBar() {
+		return nil, nil, nil
+	}
+	closes := make([]float64, len(ticks))
+	for i, k := range ticks {
+		closes[i] = k.C
+	}
+	var lines []studies.Line
+	var colors []color.RGBA
+	var legend []legendItem
+	for i, spec := range a.Studies {
+		c := theme.RGBA(a.Theme.Up)
+		if n := len(a.Theme.Series); n > 0 {
+			c = theme.RGBA(a.Theme.Series[(i+1)%n])
+		}
+		for _, l := range spec.Apply(closes) {
+			lines = append(lines, l)
+			colors = append(colors, c)
+		}
+		legend = append(legend, legendItem{label: spec.Label(), color: c})
+	}
+	return lines, colors, legend
+}
+
+// studyRange widens lo..hi to cover the study values.
+func studyRange(lines []studies.Line, lo, hi float64) (float64, float64) {
+	for _, l := range lines {
+		for _, v := range l.Values {
+			if !math.IsNaN(v) {
+				lo, hi = min(lo, v), max(hi, v)
+			}
+		}
+	}
+	return lo, hi
+}
+
+// clipLines drops the first drop values of every line.
+func clipLines(lines []studies.Line, drop int) {
+	for i := range lines {
+		lines[i].Values = lines[i].Values[drop:]
+	}
+}
+
+// overlay draws study lines, skipping the gaps where they have no value.
+func (p plot) overlay(lines []studies.Line, colors []color.RGBA) {
+	for i, l := range lines {
+		for j := 1; j < len(l.Values); j++ {
+			v0, v1 := l.Values[j-1], l.Values[j]
+			if math.IsNaN(v0) || math.IsNaN(v1) {
+				continue
+			}
+			p.s.line(p.x(j-1), p.y(v0), p.x(j), p.y(v1), 1, colors[i])
+		}
+	}
+}
+
+func (a ImageChart) message(s surface, msg string) {
+	msg = "(" + msg + ")"
+	s.text((a.Width-textWidth(msg))/2, a.Height/2, msg, theme.RGBA(a.Theme.Subtle))
+}
+
+func (a ImageChart) paintLine(s surface, ticks []Tick) {
+	if len(ticks) == 0 {
+		a.message(s, "no data")
+		return
+	}
+	lines, colors, legend := a.studyLines(ticks)
+	drop := a.clip(len(ticks), a.plotWidth())
+	ticks = ticks[drop:]
+	clipLines(lines, drop)
+	lo, hi := ticks[0].C, ticks[0].C
+	times := make([]time.Time, len(ticks))
+	for i, k := range ticks {
+		lo, hi = min(lo, k.C), max(hi, k.C)
+		times[i] = k.T
+	}
+	lo, hi = studyRange(lines, lo, hi)
+	p := a.layout(s, frameSpec{lo: lo, hi: hi, base: a.percentBase(ticks[0].C), times: times, volume: a.Volume, legend: legend})
+	p.crosshair(a.Cursor)
+	p.volume(ticks)
+	p.overlay(lines, colors)
+	for i := 1; i < len(ticks); i++ {
+		s.line(p.x(i-1), p.y(ticks[i-1].C), p.x(i), p.y(ticks[i].C), 2, p.up)
+	}
+	if len(ticks) == 1 {
+		s.rect(p.x(0)-1, p.y(ticks[0].C)-1, p.x(0)+1, p.y(ticks[0].C)+1, p.up)
+	}
+}
+
+// paintBars draws candles, or OHLC bars when ohlc is set.
+func (a ImageChart) paintBars(s surface, ticks []Tick, ohlc bool) {
+	if len(ticks) == 0 {
+		a.message(s, "no data")
+		return
+	}
+	lines, colors, legend := a.studyLines(ticks)
+	drop := a.clip(len(ticks), a.plotWidth())
+	ticks = ticks[drop:]
+	clipLines(lines, drop)
+	lo, hi := ticks[0].L, ticks[0].H
+	times := make([]time.Time, len(ticks))
+	for i, k := range ticks {
+		lo, hi = min(lo, k.L), max(hi, k.H)
+		times[i] = k.T
+	}
+	lo, hi = studyRange(lines, lo, hi)
+	volume := a.Volume && a.View != ViewRenko
+	p := a.layout(s, frameSpec{lo: lo, hi: hi, base: a.percentBase(ticks[0].C), times: times, volume: volume, legend: legend})
+	p.crosshair(a.Cursor)
+	if volume {
+		p.volume(ticks)
+	}
+
+	body := max(1, p.slot()*7/10)
+	for i, k := range ticks {
+		col := p.down
+		if k.C >= k.O {
+			col = p.up
+		}
+		x := p.x(i)
+		left := x - body/2
+		s.line(x, p.y(k.H), x, p.y(k.L), 1, col)
+		if ohlc {
+			s.line(left, p.y(k.O), x, p.y(k.O), 1, col)
+			s.line(x, p.y(k.C), left+body-1, p.y(k.C), 1, col)
+			continue
+		}
+		s.rect(left, p.y(k.O), left+body-1, p.y(k.C), col)
+	}
+	p.overlay(lines, colors)
+}
+
+// paintPointFigure draws a stack of X or O boxes per column.
+func (a ImageChart) paintPointFigure(s surface, ticks []Tick) {
+	box := a.box(ticks)
+	cols := PointFigure(ticks, box, a.Reversal)
+	if len(cols) == 0 {
+		a.message(s, "price has not moved a full box yet")
+		return
+	}
+	if limit := max(1, a.plotWidth()/6); len(cols) > limit {
+		cols = cols[len(cols)-limit:]
+	}
+	lo, hi := cols[0].Low, cols[0].High
+	times := make([]time.Time, len(cols))
+	for i, col := range cols {
+		lo, hi = min(lo, col.Low), max(hi, col.High)
+		times[i] = col.T
+	}
+	p := a.layout(s, frameSpec{lo: lo - box/2, hi: hi + box/2, base: a.percentBase(cols[0].start()), times: times})
+
+	for i, col := range cols {
+		x := p.x(i)
+		if (col.High-col.Low)/box > float64(p.h) || !(col.High >= col.Low) {
+			// boxes under a pixel tall: draw the column as a bar
+			c := p.down
+			if col.Up {
+				c = p.up
+			}
+			s.line(x, p.y(col.Low), x, p.y(col.High), 2, c)
+			continue
+		}
+		for j := 0; j <= int(math.Round((col.High-col.Low)/box)); j++ {
+			v := col.Low + float64(j)*box
+			top, bottom := p.y(v+box/2), p.y(v-box/2)
+			half := max(1, min(p.slot()-2, bottom-top-1)/2)
+			y := (top + bottom) / 2
+			if col.Up {
+				s.line(x-half, y-half, x+half, y+half, 1, p.up)
+				s.line(x-half, y+half, x+half, y-half, 1, p.up)
+				continue
+			}
+			s.line(x-half, y-half, x+half, y-half, 1, p.down)
+			s.line(x-half, y+half, x+half, y+half, 1, p.down)
+			s.line(x-half, y-half, x-half, y+half, 1, p.down)
+			s.line(x+half, y-half, x+half, y+half, 1, p.down)
+		}
+	}
+}
+
+// paintKagi draws Kagi legs: thick in the up color while yang, thin in
+// the down color while yin, joined by horizontal shoulders and waists.
+func (a ImageChart) paintKagi(s surface, ticks []Tick) {
+	legs := Kagi(ticks, a.box(ticks))
+	if len(legs) == 0 {
+		a.message(s, "price has not reversed yet")
+		return
+	}
+	if limit := max(1, a.plotWidth()/4); len(legs) > limit {
+		legs = legs[len(legs)-limit:]
+	}
+	lo, hi := legs[0].From, legs[0].From
+	times := make([]time.Time, len(legs))
+	for i, l := range legs {
+		lo, hi = min(lo, l.From, l.To), max(hi, l.From, l.To)
+		times[i] = l.T
+	}
+	p := a.layout(s, frameSpec{lo: lo, hi: hi, base: a.percentBase(legs[0].From), times: times})
+
+	stroke := func(yang bool) (int, color.RGBA) {
+		if yang {
+			return 3, p.up
+		}
+		return 1, p.down
+	}
+	for i, l := range legs {
+		x := p.x(i)
+		w, c := stroke(l.Yang)
+		if !l.Shifted {
+			s.line(x, p.y(l.From), x, p.y(l.To), w, c)
+		} else {
+			s.line(x, p.y(l.From), x, p.y(l.Shift), w, c)
+			w, c = stroke(!l.Yang)
+			s.line(x, p.y(l.Shift), x, p.y(l.To), w, c)
+		}
+		if i < len(legs)-1 {
+			w, c = stroke(l.YangAtEnd())
+			s.line(x, p.y(l.To), p.x(i+1), p.y(l.To), w, c)
+		}
+	}
+}
+
+func (a ImageChart) paintCompare(s surface, series []Series) {
+	n := 0
+	for i, sr := range series {
+		if i == 0 || len(sr.Ticks) < n {
+			n = len(sr.Ticks)
+		}
+	}
+	if n == 0 {
+		a.message(s, "no data")
+		return
+	}
+	drop := a.clip(n, a.plotWidth())
+
+	lines := make([][]float64, len(series))
+	lo, hi := 100.0, 100.0
+	for i, sr := range series {
+		visible := indexTo100(sr.Ticks[len(sr.Ticks)-n+drop:])
+		lines[i] = make([]float64, len(visible))
+		for j, k := range visible {
+			lines[i][j] = k.C
+			lo, hi = min(lo, k.C), max(hi, k.C)
+		}
+	}
+	times := make([]time.Time, n-drop)
+	for i, k := range series[0].Ticks[len(series[0].Ticks)-n+drop:] {
+		times[i] = k.T
+	}
+	colors := make([]color.RGBA, len(series))
+	legend := make([]legendItem, len(series))
+	for i, sr := range series {
+		colors[i] = theme.RGBA(a.Theme.Up)
+		if n := len(a.Theme.Series); n > 0 {
+			colors[i] = theme.RGBA(a.Theme.Series[i%n])
+		}
+		l := lines[i]
+		legend[i] = legendItem{label: fmt.Sprintf("%s %+.2f%%", sr.Name, l[len(l)-1]-100), color: colors[i]}
+	}
+	p := a.layout(s, frameSpec{lo: lo, hi: hi, base: 100, times: times, legend: legend})
+	p.crosshair(a.Cursor)
+	for i, l := range lines {
+		for j := 1; j < len(l); j++ {
+			s.line(p.x(j-1), p.y(l[j-1]), p.x(j), p.y(l[j]), 2, colors[i])
+		}
+	}
+}
+
+// mix blends a toward b by f (0..1).
+func mix(a, b color.RGBA, f float64) color.RGBA {
+	m := func(x, y uint8) uint8 { return uint8(float64(x) + (float64(y)-float64(x))*f) }
+	return color.RGBA{R: m(a.R, b.R), G: m(a.G, b.G), B: m(a.B, b.B), A: 0xff}
+}
@@ -0,0 +1,254 @@
+package chart
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+)
+
+// PivotMethod picks the formula pivot points are computed with.
+type PivotMethod int
+
+const (
+	PivotNone PivotMethod = iota
+	PivotClassic
+	PivotFibonacci
+	PivotCamarilla
+)
+
+var pivotNames = [...]string{
+	PivotNone:      "none",
+	PivotClassic:   "classic",
+	PivotFibonacci: "fib",
+	PivotCamarilla: "camarilla",
+}
+
+// String returns the name used for the method in query strings and
+// captions.
+func (m PivotMethod) String() string {
+	if m < 0 || int(m) >= len(pivotNames) {
+		return "unknown"
+	}
+	return pivotNames[m]
+}
+
+// Next returns the method after m, wrapping back to PivotNone.
+func (m PivotMethod) Next() PivotMethod {
+	return PivotMethod((int(m) + 1) % len(pivotNames))
+}
+
+// ParsePivotMethod maps a method name (plus "off", "standard",
+// "fibonacci" and "cam") back to its PivotMethod.
+func ParsePivotMethod(s string) (PivotMethod, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "off":
+		return PivotNone, true
+	case "classic", "standard":
+		return PivotClassic, true
+	case "fib", "fibonacci":
+		return PivotFibonacci, true
+	case "camarilla", "cam":
+		return PivotCamarilla, true
+	}
+	return PivotNone, false
+}
+
+// Level is a named price drawn across the chart, such as a pivot's R1.
+type Level struct {
+	Name  string
+	Price float64
+}
+
+// Pivots returns the pivot point and its resistance and support levels
+// for a session whose prior session had high h, low l and close c,
+// highest price first. PivotNone yields none.
+func Pivots(m PivotMethod, h, l, c float64) []Level {
+	p, r := (h+l+c)/3, h-l
+	switch m {
+	case PivotClassic:
+		return []Level{
+			{"R3", h + 2*(p-l)}, {"R2", p + r}, {"R1", 2*p - l},
+			{"P", p},
+			{"S1", 2*p - h}, {"S2", p - r}, {"S3", l - 2*(h-p)},
+		}
+	case PivotFibonacci:
+		return []Level{
+			{"R3", p + r}, {"R2", p + 0.618*r}, {"R1", p + 0.382*r},
+			{"P", p},
+			{"S1", p - 0.382*r}, {"S2", p - 0.618*r}, {"S3", p - r},
+		}
+	case PivotCamarilla:
+		k := r * 1.1
+		return []Level{
+			{"R4", c + k/2}, {"R3", c + k/4}, {"R2", c + k/6}, {"R1", c + k/12},
+			{"P", p},
+			{"S1", c - k/12}, {"S2", c - k/6}, {"S3", c - k/4}, {"S4", c - k/2},
+		}
+	}
+	return nil
+}
+
+// PriorSession returns the high, low and close of the last full session
+// before the one the last bar of ticks belongs to, a session being a
+// calendar day in the bars' time zone. ok is false when ticks hold a
+// single session.
+func PriorSession(ticks []Tick) (h, l, c float64, ok bool) {
+	if len(ticks) == 0 {
+		return 0, 0, 0, false
+	}
+	day := func(k Tick) [3]int {
+		y, m, d := k.T.Date()
+		return [3]int{y, int(m), d}
+	}
+	i := len(ticks) - 1
+	last := day(ticks[i])
+	for i >= 0 && day(ticks[i]) == last {
+		i--
+	}
+	if i < 0 {
+		return 0, 0, 0, false
+	}
+	h, l, c = ticks[i].H, ticks[i].L, ticks[i].C
+	prior := day(ticks[i])
+	for ; i >= 0 && day(ticks[i]) == prior; i-- {
+		h, l = max(h, ticks[i].H), min(l, ticks[i].L)
+	}
+	return h, l, c, true
+}
+
+// SessionPivots is Pivots over the prior session of ticks; nil when there
+// is none.
+func SessionPivots(m PivotMethod, ticks []Tick) []Level {
+	h, l, c, ok := PriorSession(ticks)
+	if !ok {
+		return nil
+	}
+	return Pivots(m, h, l, c)
+}
+
+// Zone is a support or resistance band: prices around which swing highs
+// and lows cluster. Touches counts the swings that fell in it.
+type Zone struct {
+	Lo, Hi  float64
+	Touches int
+}
+
+// Mid is the middle of the band.
+func (z Zone) Mid() float64 { return (z.Lo + z.Hi) / 2 }
+
+// MaxZones is how many zones the TUI and web charts show.
+const MaxZones = 4
+
+// SwingStrength is how many bars on each side a swing high must top (or a
+// swing low undercut) for SRZones to count it.
+const SwingStrength = 3
+
+// SRZones clusters the swing highs and lows of ticks into at most n
+// zones, the ones touched most often, ordered from the highest down.
+// Swings within an average bar's range of each other share a zone, and a
+// zone needs at least two of them.
+func SRZones(ticks []Tick, n int) []Zone {
+	if len(ticks) < 2*SwingStrength+1 || n <= 0 {
+		return nil
+	}
+	var swings []float64
+	var tol float64
+	for i, k := range ticks {
+		tol += k.H - k.L
+		lo, hi := i-SwingStrength, i+SwingStrength
+		if lo < 0 || hi >= len(ticks) {
+			continue
+		}
+		top, bottom := true, true
+		for j := lo; j <= hi; j++ {
+			if j == i {
+				continue
+			}
+			top = top && ticks[j].H < k.H
+			bottom = bottom && ticks[j].L > k.L
+		}
+		if top {
+			swings = append(swings, k.H)
+		}
+		if bottom {
+			swings = append(swings, k.L)
+		}
+	}
+	tol /= float64(len(ticks))
+	slices.Sort(swings)
+
+	var zones []Zone
+	for _, p := range swings {
+		if math.IsNaN(p) {
+			continue
+		}
+		if z := len(zones) - 1; z >= 0 && p-zones[z].Hi <= tol && p-zones[z].Lo <= 2*tol {
+			zones[z].Hi = p
+			zones[z].Touches++
+			continue
+		}
+		zones = append(zones, Zone{Lo: p, Hi: p, Touches: 1})
+	}
+	zones = slices.DeleteFunc(zones, func(z Zone) bool { return z.Touches < 2 })
+	slices.SortStableFunc(zones, func(a, b Zone) int { return cmp.Compare(b.Touches, a.Touches) })
+	zones = zones[:min(n, len(zones))]
+	slices.SortFunc(zones, func(a, b Zone) int { return cmp.Compare(b.Hi, a.Hi) })
+	for i := range zones {
+		// a band at least a fifth of a bar thick, so it shows
+		if pad := tol/10 - (zones[i].Hi-zones[i].Lo)/2; pad > 0 {
+			zones[i].Lo -= pad
+			zones[i].Hi += pad
+		}
+	}
+	return zones
+}
+
+// levelInk colors a level or zone by where it sits against the last
+// close: below it is support, above it resistance.
+func levelInk(p, last float64) ink {
+	if p < last {
+		return inkUp
+	}
+	return inkDown
+}
+
+// drawLevels adds zones as shaded bands and levels as dashed lines named
+// at their right end, on the blank cells of the first w columns of c;
+// last is the last close, which decides support from resistance.
+func drawLevels(c *canvas, ax yAxis, levels []Level, zones []Zone, w int, last float64) {
+	w = min(w, c.w)
+	for _, z := range zones {
+		if z.Hi < ax.value(c.h-1) || z.Lo > ax.value(0) {
+			continue // off the axis
+		}
+		k := levelInk(z.Mid(), last)
+		for y := ax.row(z.Hi); y <= ax.row(z.Lo); y++ {
+			for x := range w {
+				if c.runes[y*c.w+x] == ' ' {
+					c.set(x, y, '░', k)
+				}
+			}
+		}
+	}
+	for _, l := range levels {
+		if !ax.contains(l.Price) {
+			continue
+		}
+		k := levelInk(l.Price, last)
+		if l.Name == "P" {
+			k = inkAccent
+		}
+		y := ax.row(l.Price)
+		label := []rune(" " + l.Name)
+		for x := range w {
+			r := '╌'
+			if i := x - (w - len(label)); i >= 0 {
+				r = label[i]
+			}
+			if c.runes[y*c.w+x] == ' ' || c.runes[y*c.w+x] == '░' {
+				c.set(x, y, r, k)
+			}
+		}
+	}
+}
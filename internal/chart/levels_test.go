@@ -0,0 +1,119 @@
+package chart
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPivots(t *testing.T) {
+	cases := []struct {
+		method PivotMethod
+		want   []Level
+	}{
+		{PivotNone, nil},
+		{PivotClassic, []Level{
+			{"R3", 130}, {"R2", 120}, {"R1", 110}, {"P", 100}, {"S1", 90}, {"S2", 80}, {"S3", 70},
+		}},
+		{PivotFibonacci, []Level{
+			{"R3", 120}, {"R2", 112.36}, {"R1", 107.64}, {"P", 100}, {"S1", 92.36}, {"S2", 87.64}, {"S3", 80},
+		}},
+		{PivotCamarilla, []Level{
+			{"R4", 111}, {"R3", 105.5}, {"R2", 103 + 2.0/3}, {"R1", 101 + 5.0/6}, {"P", 100},
+			{"S1", 98 + 1.0/6}, {"S2", 96 + 1.0/3}, {"S3", 94.5}, {"S4", 89},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.method.String(), func(t *testing.T) {
+			got := Pivots(c.method, 110, 90, 100)
+			if len(got) != len(c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+			for i, l := range got {
+				if l.Name != c.want[i].Name || !near(l.Price, c.want[i].Price) {
+					t.Errorf("level %d = %v, want %v", i, l, c.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestPriorSession(t *testing.T) {
+	day := func(d, i int, h, l, c float64) Tick {
+		return Tick{T: goldenT0.AddDate(0, 0, d).Add(time.Duration(i) * time.Minute), H: h, L: l, C: c}
+	}
+	cases := []struct {
+		name    string
+		ticks   []Tick
+		h, l, c float64
+		ok      bool
+	}{
+		{"no bars", nil, 0, 0, 0, false},
+		{"one session", []Tick{day(0, 0, 101, 99, 100), day(0, 1, 102, 98, 101)}, 0, 0, 0, false},
+		{"yesterday", []Tick{
+			day(0, 0, 101, 99, 100), day(0, 1, 103, 98, 102),
+			day(1, 0, 110, 90, 105),
+		}, 103, 98, 102, true},
+		{"the day before today only", []Tick{
+			day(0, 0, 200, 50, 100),
+			day(1, 0, 101, 99, 100), day(1, 1, 102, 97, 99),
+			day(2, 0, 110, 90, 105), day(2, 1, 111, 89, 106),
+		}, 102, 97, 99, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			h, l, cl, ok := PriorSession(c.ticks)
+			if h != c.h || l != c.l || cl != c.c || ok != c.ok {
+				t.Errorf("PriorSession = %g, %g, %g, %v; want %g, %g, %g, %v", h, l, cl, ok, c.h, c.l, c.c, c.ok)
+			}
+		})
+	}
+}
+
+// zigzag walks the closes through points six steps a leg, each bar a
+// point either side of its close.
+func zigzag(points ...float64) []Tick {
+	var out []Tick
+	add := func(p float64) { out = append(out, bar(len(out), p, p+0.5, p-0.5, p, 100)) }
+	add(points[0])
+	for i := 1; i < len(points); i++ {
+		for s := 1; s <= 6; s++ {
+			add(points[i-1] + (points[i]-points[i-1])*float64(s)/6)
+		}
+	}
+	return out
+}
+
+func TestSRZones(t *testing.T) {
+	cases := []struct {
+		name  string
+		ticks []Tick
+		n     int
+		want  []Zone
+	}{
+		{"too few bars", zigzag(100, 106), MaxZones, nil},
+		{"near swings merge", zigzag(103, 106, 100, 106.3, 100.2, 103), MaxZones, []Zone{
+			{106.5, 106.8, 2}, {99.5, 99.7, 2},
+		}},
+		{"far swings don't", zigzag(103, 106, 100, 109, 95, 103), MaxZones, nil},
+		{"most touched kept", zigzag(103, 106, 100, 106.3, 100.2, 106.2, 103), 1, []Zone{
+			{106.5, 106.8, 3},
+		}},
+		{"thin band padded", zigzag(103, 106, 100, 108, 100, 103), MaxZones, []Zone{
+			{99.4, 99.6, 2},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := SRZones(c.ticks, c.n)
+			if len(got) != len(c.want) {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+			for i, z := range got {
+				w := c.want[i]
+				if !near(z.Lo, w.Lo) || !near(z.Hi, w.Hi) || z.Touches != w.Touches {
+					t.Errorf("zone %d = %v, want %v", i, z, w)
+				}
+			}
+		})
+	}
+}
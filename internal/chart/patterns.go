@@ -0,0 +1,222 @@
+package chart
+
+import (
+	"math"
+	"time"
+)
+
+// Pattern is a candlestick pattern FindPatterns recognises.
+type Pattern string
+
+const (
+	PatternDoji             Pattern = "doji"
+	PatternHammer           Pattern = "hammer"
+	PatternBullishEngulfing Pattern = "bullish engulfing"
+	PatternBearishEngulfing Pattern = "bearish engulfing"
+	PatternMorningStar      Pattern = "morning star"
+	PatternEveningStar      Pattern = "evening star"
+	PatternInsideBar        Pattern = "inside bar"
+	PatternOutsideBar       Pattern = "outside bar"
+	PatternThreeSoldiers    Pattern = "three white soldiers"
+	PatternThreeCrows       Pattern = "three black crows"
+)
+
+// Bias is +1 for a pattern read as bullish, -1 for bearish and 0 for one
+// that only marks indecision or a range change.
+func (p Pattern) Bias() int {
+	switch p {
+	case PatternHammer, PatternBullishEngulfing, PatternMorningStar, PatternThreeSoldiers:
+		return 1
+	case PatternBearishEngulfing, PatternEveningStar, PatternThreeCrows:
+		return -1
+	}
+	return 0
+}
+
+// Short is the pattern's abbreviation for chart labels.
+func (p Pattern) Short() string {
+	for _, d := range detectors {
+		if d.pattern == p {
+			return d.short
+		}
+	}
+	return "?"
+}
+
+// glyph is the character-cell marker for p, its ink and whether it sits
+// above the bar: bullish patterns point up from below, bearish ones down
+// from above.
+func (p Pattern) glyph() (rune, ink, bool) {
+	switch p.Bias() {
+	case 1:
+		return '△', inkUp, false
+	case -1:
+		return '▽', inkDown, true
+	}
+	return '◇', inkCursor, true
+}
+
+// PatternMatch is a pattern found in a series: Bar is the index of its
+// last bar, the one it completes on, and Time that bar's time.
+type PatternMatch struct {
+	Pattern Pattern
+	Bar     int
+	Time    time.Time
+}
+
+// String reads like "14:35 hammer".
+func (m PatternMatch) String() string {
+	return m.Time.Format("15:04") + " " + string(m.Pattern)
+}
+
+// detectors lists the recognisers in the order FindPatterns reports
+// matches completing on the same bar. Each is handed exactly bars ticks,
+// the last being the bar the pattern would complete on.
+var detectors = []struct {
+	pattern Pattern
+	short   string
+	bars    int
+	match   func([]Tick) bool
+}{
+	{PatternDoji, "Dj", 1, isDoji},
+	{PatternHammer, "Hm", 1, isHammer},
+	{PatternBullishEngulfing, "E+", 2, isBullishEngulfing},
+	{PatternBearishEngulfing, "E-", 2, isBearishEngulfing},
+	{PatternMorningStar, "MS", 3, isMorningStar},
+	{PatternEveningStar, "ES", 3, isEveningStar},
+	{PatternInsideBar, "IB", 2, isInsideBar},
+	{PatternOutsideBar, "OB", 2, isOutsideBar},
+	{PatternThreeSoldiers, "3W", 3, isThreeSoldiers},
+	{PatternThreeCrows, "3B", 3, isThreeCrows},
+}
+
+// FindPatterns runs every detector over ticks and returns the matches in
+// bar order.
+func FindPatterns(ticks []Tick) []PatternMatch {
+	var out []PatternMatch
+	for i := range ticks {
+		for _, d := range detectors {
+			if i+1 < d.bars || !d.match(ticks[i+1-d.bars:i+1]) {
+				continue
+			}
+			out = append(out, PatternMatch{Pattern: d.pattern, Bar: i, Time: ticks[i].T})
+		}
+	}
+	return out
+}
+
+// PatternsAt returns the matches of ms that complete on bar i of ticks.
+func PatternsAt(ms []PatternMatch, ticks []Tick, i int) []PatternMatch {
+	var out []PatternMatch
+	for _, m := range ms {
+		if j, ok := BarAt(ticks, m.Time); ok && j == i {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// candle measures of a bar: its body, full range and the wicks above and
+// below the body.
+func candleBody(k Tick) float64  { return math.Abs(k.C - k.O) }
+func candleRange(k Tick) float64 { return k.H - k.L }
+func upperWick(k Tick) float64   { return k.H - max(k.O, k.C) }
+func lowerWick(k Tick) float64   { return min(k.O, k.C) - k.L }
+func isRising(k Tick) bool       { return k.C > k.O }
+func isFalling(k Tick) bool      { return k.C < k.O }
+func bodyMid(k Tick) float64     { return (k.O + k.C) / 2 }
+
+// longBody reports a body that fills at least half of its bar.
+func longBody(k Tick) bool { return candleRange(k) > 0 && candleBody(k) >= candleRange(k)/2 }
+
+// isDoji: open and close within a tenth of the bar's range.
+func isDoji(k []Tick) bool {
+	return candleRange(k[0]) > 0 && candleBody(k[0]) <= candleRange(k[0])/10
+}
+
+// isHammer: a small body at the top of the bar, with a lower wick at least
+// twice the body and next to no upper wick.
+func isHammer(k []Tick) bool {
+	b := candleBody(k[0])
+	return b > 0 && !isDoji(k) && lowerWick(k[0]) >= 2*b && upperWick(k[0]) <= candleRange(k[0])/10
+}
+
+// isBullishEngulfing: a falling bar whose body a rising bar's body covers.
+func isBullishEngulfing(k []Tick) bool {
+	a, b := k[0], k[1]
+	return isFalling(a) && isRising(b) && b.O <= a.C && b.C >= a.O && candleBody(b) > candleBody(a)
+}
+
+// isBearishEngulfing: a rising bar whose body a falling bar's body covers.
+func isBearishEngulfing(k []Tick) bool {
+	a, b := k[0], k[1]
+	return isRising(a) && isFalling(b) && b.O >= a.C && b.C <= a.O && candleBody(b) > candleBody(a)
+}
+
+// isMorningStar: a long falling bar, a small-bodied bar below its close,
+// then a rising bar closing past the middle of the first.
+func isMorningStar(k []Tick) bool {
+	a, s, c := k[0], k[1], k[2]
+	return isFalling(a) && longBody(a) && candleBody(s) <= candleBody(a)*0.3 && bodyMid(s) < a.C &&
+		isRising(c) && c.C > bodyMid(a)
+}
+
+// isEveningStar: a long rising bar, a small-bodied bar above its close,
+// then a falling bar closing past the middle of the first.
+func isEveningStar(k []Tick) bool {
+	a, s, c := k[0], k[1], k[2]
+	return isRising(a) && longBody(a) && candleBody(s) <= candleBody(a)*0.3 && bodyMid(s) > a.C &&
+		isFalling(c) && c.C < bodyMid(a)
+}
+
+// isInsideBar: a bar whose whole range lies strictly within the previous
+// one's.
+func isInsideBar(k []Tick) bool {
+	return k[1].H < k[0].H && k[1].L > k[0].L
+}
+
+// isOutsideBar: a bar whose range strictly covers the previous one's.
+func isOutsideBar(k []Tick) bool {
+	return k[1].H > k[0].H && k[1].L < k[0].L
+}
+
+// isThreeSoldiers: three long rising bars, each opening within the body of
+// the one before and closing higher.
+func isThreeSoldiers(k []Tick) bool {
+	for i, t := range k {
+		if !isRising(t) || !longBody(t) {
+			return false
+		}
+		if i > 0 && (t.O < k[i-1].O || t.O > k[i-1].C || t.C <= k[i-1].C) {
+			return false
+		}
+	}
+	return true
+}
+
+// isThreeCrows: three long falling bars, each opening within the body of
+// the one before and closing lower.
+func isThreeCrows(k []Tick) bool {
+	for i, t := range k {
+		if !isFalling(t) || !longBody(t) {
+			return false
+		}
+		if i > 0 && (t.O > k[i-1].O || t.O < k[i-1].C || t.C >= k[i-1].C) {
+			return false
+		}
+	}
+	return true
+}
+
+// appendPatternMarks places ms on the bars of ticks.
+func appendPatternMarks(out []mark, ms []PatternMatch, ticks []Tick) []mark {
+	for _, m := range ms {
+		i, ok := BarAt(ticks, m.Time)
+		if !ok {
+			continue
+		}
+		r, k, above := m.Pattern.glyph()
+		out = append(out, mark{bar: i, hi: ticks[i].H, lo: ticks[i].L, glyph: r, ink: k, above: above})
+	}
+	return out
+}
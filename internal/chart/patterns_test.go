@@ -0,0 +1,166 @@
+package chart
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+// candle is an OHLC bar for the detector tables; the time is filled in
+// from its position.
+type candle [4]float64 // open, high, low, close
+
+func candles(cs ...candle) []Tick {
+	out := make([]Tick, len(cs))
+	for i, c := range cs {
+		out[i] = bar(i, c[0], c[1], c[2], c[3], 100)
+	}
+	return out
+}
+
+// patternCases holds, per pattern, bars handed to its detector and
+// whether it should fire.
+var patternCases = map[Pattern][]struct {
+	name string
+	bars []candle
+	want bool
+}{
+	PatternDoji: {
+		{"open equals close", []candle{{100, 101, 99, 100}}, true},
+		{"small body", []candle{{100, 101, 99, 100.15}}, true},
+		{"body just over a tenth", []candle{{100, 101, 99, 100.21}}, false},
+		{"marubozu", []candle{{99, 101, 99, 101}}, false},
+		{"flat bar", []candle{{100, 100, 100, 100}}, false},
+	},
+	PatternHammer: {
+		{"rising hammer", []candle{{100, 100.6, 98, 100.5}}, true},
+		{"falling hammer", []candle{{100.5, 100.55, 98, 100}}, true},
+		{"lower wick under twice the body", []candle{{100, 101.05, 99, 101}}, false},
+		{"upper wick too long", []candle{{100, 101.5, 98, 100.5}}, false},
+		{"inverted hammer", []candle{{100, 102, 99.95, 100.5}}, false},
+		{"doji with a long lower wick", []candle{{100, 100.05, 98, 100}}, false},
+		{"flat bar", []candle{{100, 100, 100, 100}}, false},
+	},
+	PatternBullishEngulfing: {
+		{"engulfs", []candle{{101, 101.5, 99.5, 100}, {99.8, 102, 99.5, 101.5}}, true},
+		{"body to body", []candle{{101, 101.5, 99.5, 100}, {100, 101.5, 99.5, 101.2}}, true},
+		{"closes inside the prior body", []candle{{101, 101.5, 99.5, 100}, {99.8, 101, 99.5, 100.8}}, false},
+		{"prior bar rising", []candle{{100, 101.5, 99.5, 101}, {99.8, 102, 99.5, 101.5}}, false},
+		{"engulfing bar falling", []candle{{101, 101.5, 99.5, 100}, {101.5, 102, 99, 99.5}}, false},
+	},
+	PatternBearishEngulfing: {
+		{"engulfs", []candle{{100, 101.5, 99.5, 101}, {101.2, 101.5, 99, 99.5}}, true},
+		{"closes inside the prior body", []candle{{100, 101.5, 99.5, 101}, {101.2, 101.5, 99, 100.2}}, false},
+		{"prior bar falling", []candle{{101, 101.5, 99.5, 100}, {101.2, 101.5, 99, 99.5}}, false},
+		{"engulfing bar rising", []candle{{100, 101.5, 99.5, 101}, {99.5, 102, 99, 101.5}}, false},
+	},
+	PatternMorningStar: {
+		{"classic", []candle{{105, 105.5, 99.5, 100}, {99.5, 100, 98.5, 99.2}, {99.5, 103.5, 99.3, 103}}, true},
+		{"third bar stops short of the middle", []candle{{105, 105.5, 99.5, 100}, {99.5, 100, 98.5, 99.2}, {99.5, 102.2, 99.3, 102}}, false},
+		{"star body too big", []candle{{105, 105.5, 99.5, 100}, {101, 101.5, 97.5, 98}, {98, 103.5, 97.8, 103}}, false},
+		{"star above the first close", []candle{{105, 105.5, 99.5, 100}, {100.5, 101, 100.2, 100.7}, {100.5, 103.5, 100.3, 103}}, false},
+		{"first bar short", []candle{{101, 105, 96, 100}, {99.5, 100, 98.5, 99.2}, {99.5, 103.5, 99.3, 103}}, false},
+	},
+	PatternEveningStar: {
+		{"classic", []candle{{100, 105.5, 99.5, 105}, {105.5, 106.5, 105, 105.8}, {105.5, 105.7, 101.5, 102}}, true},
+		{"third bar stops short of the middle", []candle{{100, 105.5, 99.5, 105}, {105.5, 106.5, 105, 105.8}, {105.5, 105.7, 102.8, 103}}, false},
+		{"star below the first close", []candle{{100, 105.5, 99.5, 105}, {104.5, 104.8, 104, 104.3}, {104.5, 104.7, 101.5, 102}}, false},
+		{"last bar rising", []candle{{100, 105.5, 99.5, 105}, {105.5, 106.5, 105, 105.8}, {105.5, 107, 105.2, 106.5}}, false},
+	},
+	PatternInsideBar: {
+		{"inside", []candle{{100, 102, 98, 101}, {100.5, 101.5, 99, 100}}, true},
+		{"equal high", []candle{{100, 102, 98, 101}, {100.5, 102, 99, 100}}, false},
+		{"breaks the low", []candle{{100, 102, 98, 101}, {100.5, 101.5, 97.5, 100}}, false},
+	},
+	PatternOutsideBar: {
+		{"outside", []candle{{100, 101, 99, 100.5}, {100.5, 101.5, 98.5, 99}}, true},
+		{"equal low", []candle{{100, 101, 99, 100.5}, {100.5, 101.5, 99, 99.5}}, false},
+		{"inside", []candle{{100, 102, 98, 101}, {100.5, 101.5, 99, 100}}, false},
+	},
+	PatternThreeSoldiers: {
+		{"three advancing bars", []candle{{100, 101.1, 99.9, 101}, {100.5, 102.1, 100.4, 102}, {101.5, 103.1, 101.4, 103}}, true},
+		{"opens above the prior close", []candle{{100, 101.1, 99.9, 101}, {101.5, 102.6, 101.4, 102.5}, {102, 103.6, 101.9, 103.5}}, false},
+		{"closes lower", []candle{{100, 101.1, 99.9, 101}, {100.5, 102.1, 100.4, 102}, {101.5, 102, 101, 101.8}}, false},
+		{"short middle bar", []candle{{100, 101.1, 99.9, 101}, {100.5, 103, 99, 101.5}, {101.5, 103.1, 101.4, 103}}, false},
+		{"one falling bar", []candle{{100, 101.1, 99.9, 101}, {102, 102.1, 100.4, 100.5}, {101.5, 103.1, 101.4, 103}}, false},
+	},
+	PatternThreeCrows: {
+		{"three declining bars", []candle{{103, 103.1, 101.9, 102}, {102.5, 102.6, 100.9, 101}, {101.5, 101.6, 99.9, 100}}, true},
+		{"opens below the prior close", []candle{{103, 103.1, 101.9, 102}, {101.5, 101.6, 100.4, 100.5}, {100, 100.1, 98.9, 99}}, false},
+		{"closes higher", []candle{{103, 103.1, 101.9, 102}, {102.5, 102.6, 100.9, 101}, {101.5, 102, 101, 101.2}}, false},
+	},
+}
+
+func TestPatternDetectors(t *testing.T) {
+	for _, d := range detectors {
+		cases := patternCases[d.pattern]
+		if len(cases) == 0 {
+			t.Errorf("%s: no test cases", d.pattern)
+		}
+		for _, tc := range cases {
+			t.Run(string(d.pattern)+"/"+tc.name, func(t *testing.T) {
+				ticks := candles(tc.bars...)
+				if len(ticks) != d.bars {
+					t.Fatalf("case has %d bars, detector takes %d", len(ticks), d.bars)
+				}
+				if got := d.match(ticks); got != tc.want {
+					t.Errorf("got %v, want %v", got, tc.want)
+				}
+			})
+		}
+	}
+}
+
+func TestFindPatterns(t *testing.T) {
+	ticks := candles(
+		candle{105, 105.5, 99.5, 100},  // long falling bar
+		candle{99.5, 100, 98.5, 99.2},  // the star
+		candle{99.5, 103.5, 99.3, 103}, // completes the morning star
+		candle{101, 102, 100, 101},     // doji, inside bar
+	)
+	var got []Pattern
+	for _, m := range FindPatterns(ticks) {
+		if !m.Time.Equal(ticks[m.Bar].T) {
+			t.Errorf("%s: time %v is not bar %d's", m.Pattern, m.Time, m.Bar)
+		}
+		got = append(got, m.Pattern)
+	}
+	want := []Pattern{PatternMorningStar, PatternDoji, PatternInsideBar}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	at := PatternsAt(FindPatterns(ticks), ticks, 3)
+	if len(at) != 2 || at[0].Pattern != PatternDoji || at[1].Pattern != PatternInsideBar {
+		t.Errorf("PatternsAt(3) = %v", at)
+	}
+	if FindPatterns(nil) != nil {
+		t.Error("patterns found in no bars")
+	}
+}
+
+func TestPatternMarks(t *testing.T) {
+	ticks := candles(
+		candle{100, 101, 99, 100.5},
+		candle{100.5, 102, 100, 101},
+		candle{101, 103, 100.5, 102},
+	)
+	ms := []PatternMatch{
+		{Pattern: PatternHammer, Time: ticks[0].T},
+		{Pattern: PatternEveningStar, Time: ticks[1].T},
+		{Pattern: PatternDoji, Time: ticks[2].T},
+		{Pattern: PatternInsideBar, Time: ticks[2].T.Add(time.Hour)}, // off the bars
+	}
+	want := []mark{
+		{bar: 0, hi: 101, lo: 99, glyph: '△', ink: inkUp},
+		{bar: 1, hi: 102, lo: 100, glyph: '▽', ink: inkDown, above: true},
+		{bar: 2, hi: 103, lo: 100.5, glyph: '◇', ink: inkCursor, above: true},
+	}
+	if got := appendPatternMarks(nil, ms, ticks); !slices.Equal(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	// matches found on the whole series land on a visible slice by time
+	if got := appendPatternMarks(nil, ms[1:2], ticks[1:]); len(got) != 1 || got[0].bar != 0 {
+		t.Errorf("on the last two bars: got %+v, want the evening star on bar 0", got)
+	}
+}
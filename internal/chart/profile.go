@@ -0,0 +1,261 @@
+package chart
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// ProfileMode selects the activity-at-price histogram drawn beside the
+// bars.
+type ProfileMode int
+
+const (
+	ProfileNone   ProfileMode = iota
+	ProfileVolume             // volume traded at each price
+	ProfileTPO                // market profile: one letter per period at each price
+)
+
+var profileNames = [...]string{
+	ProfileNone:   "none",
+	ProfileVolume: "volume",
+	ProfileTPO:    "tpo",
+}
+
+// String returns the name used for the mode in query strings and captions.
+func (m ProfileMode) String() string {
+	if m < 0 || int(m) >= len(profileNames) {
+		return "unknown"
+	}
+	return profileNames[m]
+}
+
+// Next returns the mode after m, wrapping back to ProfileNone.
+func (m ProfileMode) Next() ProfileMode {
+	return ProfileMode((int(m) + 1) % len(profileNames))
+}
+
+// ParseProfileMode maps a mode name (plus "vol", "vp", "market" and "off")
+// back to its ProfileMode.
+func ParseProfileMode(s string) (ProfileMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "off":
+		return ProfileNone, true
+	case "volume", "vol", "vp":
+		return ProfileVolume, true
+	case "tpo", "market":
+		return ProfileTPO, true
+	}
+	return ProfileNone, false
+}
+
+// ValueAreaShare is the share of the activity the value area holds.
+const ValueAreaShare = 0.70
+
+// TPOPeriod is the time each TPO letter stands for, unless the bars are
+// further apart than that.
+const TPOPeriod = 30 * time.Minute
+
+// Profile is activity binned by price: volume, or the number of TPO
+// periods that traded there.
+type Profile struct {
+	Lo, Step float64   // bin i covers [Lo+i*Step, Lo+(i+1)*Step)
+	Counts   []float64 // per bin, lowest price first
+	Letters  []string  // TPO profiles only: the periods seen in each bin
+
+	// POC is the bin with the most activity (the point of control);
+	// VAL..VAH the value area around it holding ValueAreaShare of it all.
+	POC, VAL, VAH int
+}
+
+// Price returns the middle of bin i.
+func (p Profile) Price(i int) float64 {
+	return p.Lo + (float64(i)+0.5)*p.Step
+}
+
+// VolumeProfile spreads each bar's volume evenly over the bins its range
+// covers, with bins equal slices of the ticks' price range.
+func VolumeProfile(ticks []Tick, bins int) Profile {
+	return newProfile(ticks, bins, ProfileVolume)
+}
+
+// TPOProfile records, for each of bins price slices, which TPOPeriod
+// periods traded there; Counts holds how many did.
+func TPOProfile(ticks []Tick, bins int) Profile {
+	return newProfile(ticks, bins, ProfileTPO)
+}
+
+func newProfile(ticks []Tick, bins int, mode ProfileMode) Profile {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, k := range ticks {
+		if finite(k.L) && finite(k.H) {
+			lo, hi = min(lo, k.L), max(hi, k.H)
+		}
+	}
+	if bins <= 0 || lo > hi {
+		return Profile{}
+	}
+	if hi == lo {
+		lo, hi = lo-0.5, hi+0.5
+	}
+	p := Profile{Lo: lo, Step: (hi - lo) / float64(bins)}
+	bin := func(v float64) int {
+		return min(max(int((v-lo)/p.Step), 0), bins-1)
+	}
+	p.Counts, p.Letters = binActivity(ticks, bins, bin, mode)
+	p.POC, p.VAL, p.VAH = valueArea(p.Counts)
+	return p
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// binActivity builds the counts (and, for TPO, letters) of n bins, bin
+// mapping a price to its bin.
+func binActivity(ticks []Tick, n int, bin func(float64) int, mode ProfileMode) ([]float64, []string) {
+	counts := make([]float64, n)
+	if mode == ProfileTPO {
+		return counts, tpoLetters(ticks, counts, bin)
+	}
+	for _, k := range ticks {
+		if !finite(k.L) || !finite(k.H) || k.V <= 0 {
+			continue
+		}
+		b0, b1 := bin(min(k.L, k.H)), bin(max(k.L, k.H))
+		share := float64(k.V) / float64(b1-b0+1)
+		for b := b0; b <= b1; b++ {
+			counts[b] += share
+		}
+	}
+	return counts, nil
+}
+
+// tpoLetters fills counts with the number of periods that traded in each
+// bin and returns their letters: A, B, … Z, a, … z, then A again.
+func tpoLetters(ticks []Tick, counts []float64, bin func(float64) int) []string {
+	letters := make([][]byte, len(counts))
+	seen := make([]int, len(counts)) // last period+1 recorded per bin
+	if len(ticks) == 0 {
+		return make([]string, len(counts))
+	}
+	period := TPOPeriod
+	if len(ticks) > 1 {
+		period = max(period, ticks[1].T.Sub(ticks[0].T))
+	}
+	t0 := ticks[0].T.Truncate(period)
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	for _, k := range ticks {
+		if !finite(k.L) || !finite(k.H) {
+			continue
+		}
+		j := int(k.T.Sub(t0) / period)
+		for b := bin(min(k.L, k.H)); b <= bin(max(k.L, k.H)); b++ {
+			if seen[b] == j+1 {
+				continue
+			}
+			seen[b] = j + 1
+			counts[b]++
+			letters[b] = append(letters[b], alphabet[j%len(alphabet)])
+		}
+	}
+	out := make([]string, len(counts))
+	for i, l := range letters {
+		out[i] = string(l)
+	}
+	return out
+}
+
+// valueArea finds the busiest bin and grows a range around it, taking the
+// busier neighbour each step, until it holds ValueAreaShare of the total.
+func valueArea(counts []float64) (poc, lo, hi int) {
+	total := 0.0
+	for i, c := range counts {
+		total += c
+		if c > counts[poc] {
+			poc = i
+		}
+	}
+	lo, hi = poc, poc
+	for acc := counts[poc]; acc < ValueAreaShare*total; {
+		up, down := -1.0, -1.0
+		if hi+1 < len(counts) {
+			up = counts[hi+1]
+		}
+		if lo > 0 {
+			down = counts[lo-1]
+		}
+		switch {
+		case up < 0 && down < 0:
+			return poc, lo, hi
+		case up >= down:
+			hi++
+			acc += up
+		default:
+			lo--
+			acc += down
+		}
+	}
+	return poc, lo, hi
+}
+
+// profileWidth is how many columns the histogram beside a chartW-wide plot
+// takes.
+func profileWidth(chartW int) int {
+	return min(max(chartW/5, 8), 24)
+}
+
+// profileGap is the columns a profile pw wide adds after the bars,
+// counting the blank one that separates them.
+func profileGap(pw int) int {
+	if pw == 0 {
+		return 0
+	}
+	return pw + 1
+}
+
+// profileEighths draws fractions of a cell for the volume histogram.
+var profileEighths = []rune("▏▎▍▌▋▊▉█")
+
+// drawProfile draws the profile of ticks in columns x0..x0+w-1 of c, one
+// bin per canvas row so each bar lines up with the axis label beside it.
+// The point of control is drawn in the accent color and bins outside the
+// value area dimmed.
+func drawProfile(c *canvas, ax yAxis, ticks []Tick, x0, w int, mode ProfileMode) {
+	rows := c.h
+	bin := func(p float64) int { return rows - 1 - ax.row(p) }
+	counts, letters := binActivity(ticks, rows, bin, mode)
+	poc, val, vah := valueArea(counts)
+	top := counts[poc]
+	if top <= 0 {
+		return
+	}
+	for b, n := range counts {
+		if n <= 0 {
+			continue
+		}
+		y := rows - 1 - b
+		k := inkCursor
+		switch {
+		case b == poc:
+			k = inkAccent
+		case b >= val && b <= vah:
+			k = inkNone
+		}
+		if mode == ProfileTPO {
+			l := letters[b]
+			if len(l) > w {
+				l = l[:w-1] + "…"
+			}
+			for i, r := range []rune(l) {
+				c.set(x0+i, y, r, k)
+			}
+			continue
+		}
+		eighths := max(1, int(math.Round(n/top*float64(w*8))))
+		for x := 0; eighths > 0; x++ {
+			c.set(x0+x, y, profileEighths[min(eighths, 8)-1], k)
+			eighths -= 8
+		}
+	}
+}
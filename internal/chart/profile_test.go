@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValueArea(t *testing.T) {
+	cases := []struct {
+		name        string
+		counts      []float64
+		poc, lo, hi int
+	}{
+		{"one bin", []float64{5}, 0, 0, 0},
+		{"nothing traded", []float64{0, 0, 0}, 0, 0, 0},
+		{"busier side first", []float64{1, 2, 10, 3, 1}, 2, 2, 3},
+		{"ties grow upward", []float64{1, 3, 10, 3, 1}, 2, 2, 3},
+		{"first of equal bins", []float64{4, 4, 4, 4, 4}, 0, 0, 3},
+		{"grows down from the top", []float64{1, 1, 3}, 2, 1, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			poc, lo, hi := valueArea(c.counts)
+			if poc != c.poc || lo != c.lo || hi != c.hi {
+				t.Errorf("valueArea = %d, %d..%d; want %d, %d..%d", poc, lo, hi, c.poc, c.lo, c.hi)
+			}
+		})
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	// two bars, the second half an hour on and three times the volume
+	ticks := []Tick{
+		{T: goldenT0, O: 100, H: 101, L: 100, C: 101, V: 100},
+		{T: goldenT0.Add(30 * time.Minute), O: 104, H: 105, L: 104, C: 105, V: 300},
+	}
+	vp := VolumeProfile(ticks, 5)
+	if vp.Lo != 100 || vp.Step != 1 {
+		t.Errorf("volume bins start %g, step %g; want 100, 1", vp.Lo, vp.Step)
+	}
+	if want := []float64{50, 50, 0, 0, 300}; !slices.Equal(vp.Counts, want) {
+		t.Errorf("volume counts %v, want %v", vp.Counts, want)
+	}
+	if vp.POC != 4 || vp.VAL != 4 || vp.VAH != 4 {
+		t.Errorf("volume POC %d, value area %d..%d; want 4, 4..4", vp.POC, vp.VAL, vp.VAH)
+	}
+	if got := vp.Price(vp.POC); got != 104.5 {
+		t.Errorf("POC price %g, want 104.5", got)
+	}
+
+	tpo := TPOProfile(ticks, 5)
+	if want := []string{"A", "A", "", "", "B"}; !slices.Equal(tpo.Letters, want) {
+		t.Errorf("TPO letters %q, want %q", tpo.Letters, want)
+	}
+	if want := []float64{1, 1, 0, 0, 1}; !slices.Equal(tpo.Counts, want) {
+		t.Errorf("TPO counts %v, want %v", tpo.Counts, want)
+	}
+}
@@ -0,0 +1,114 @@
+package chart
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Quote is a symbol's latest price against the previous session's close,
+// with the session's closes so far for a sparkline.
+type Quote struct {
+	Symbol    string
+	Last      float64
+	PrevClose float64
+	Closes    []float64
+}
+
+// Change returns the move since the previous close in percent (0 when the
+// previous close is unknown).
+func (q Quote) Change() float64 {
+	if q.PrevClose == 0 {
+		return 0
+	}
+	return (q.Last/q.PrevClose - 1) * 100
+}
+
+type yfQuoteResp struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				RegularMarketPrice float64 `json:"regularMarketPrice"`
+				ChartPreviousClose float64 `json:"chartPreviousClose"`
+				PreviousClose      float64 `json:"previousClose"`
+			} `json:"meta"`
+			Indicators struct {
+				Quote []struct {
+					Close []float64 `json:"close"`
+				} `json:"quote"`
+			} `json:"indicators"`
+		} `json:"result"`
+	} `json:"chart"`
+}
+
+// FetchQuote fetches symbol's quote and today's 5-minute closes.
+func FetchQuote(symbol string) (Quote, error) {
+	url := fmt.Sprintf("https://query1.finance.yahoo.com/v8/finance/chart/%s?range=1d&interval=5m", symbol)
+	req, _ := http.NewRequest("GET", url, nil)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (TickerForge)")
+
+	client := &http.Client{Timeout: 8 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Quote{}, fmt.Errorf("yahoo request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var data yfQuoteResp
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return Quote{}, fmt.Errorf("decode: %w", err)
+	}
+	if len(data.Chart.Result) == 0 {
+		return Quote{}, fmt.Errorf("no data for %s", symbol)
+	}
+	r := data.Chart.Result[0]
+	q := Quote{Symbol: strings.ToUpper(symbol), Last: r.Meta.RegularMarketPrice, PrevClose: r.Meta.ChartPreviousClose}
+	if q.PrevClose == 0 {
+		q.PrevClose = r.Meta.PreviousClose
+	}
+	if len(r.Indicators.Quote) > 0 {
+		for _, c := range r.Indicators.Quote[0].Close {
+			if c != 0 && !math.IsNaN(c) {
+				q.Closes = append(q.Closes, c)
+			}
+		}
+	}
+	if q.Last == 0 && len(q.Closes) > 0 {
+		q.Last = q.Closes[len(q.Closes)-1]
+	}
+	if q.Last == 0 {
+		return Quote{}, fmt.Errorf("no price for %s", symbol)
+	}
+	return q, nil
+}
+
+// FetchQuotes fetches every symbol's quote concurrently, keyed by
+// upper-case symbol. Symbols that fail are left out and their errors
+// joined into err.
+func FetchQuotes(symbols []string) (map[string]Quote, error) {
+	out := make(map[string]Quote, len(symbols))
+	errs := make([]error, len(symbols))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, sym := range symbols {
+		wg.Add(1)
+		go func(i int, sym string) {
+			defer wg.Done()
+			q, err := FetchQuote(sym)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", sym, err)
+				return
+			}
+			mu.Lock()
+			out[q.Symbol] = q
+			mu.Unlock()
+		}(i, sym)
+	}
+	wg.Wait()
+	return out, errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// surface is what the image renderers draw on. Coordinates are pixels
+// with the origin at the top left.
+type surface interface {
+	// rect fills the rectangle [x0,x1]×[y0,y1] (either order).
+	rect(x0, y0, x1, y1 int, c color.RGBA)
+	// line draws a straight line width pixels thick.
+	line(x0, y0, x1, y1, width int, c color.RGBA)
+	// text draws s with its baseline at y, starting at x.
+	text(x, y int, s string, c color.RGBA)
+}
+
+// Glyph metrics of the image font (basicfont.Face7x13).
+const (
+	glyphW = 7
+	glyphH = 13
+)
+
+// textWidth is the pixel width of s in the image font.
+func textWidth(s string) int {
+	return len([]rune(s)) * glyphW
+}
+
+// raster is a surface backed by an in-memory RGBA image.
+type raster struct {
+	img *image.RGBA
+}
+
+func newRaster(w, h int, bg color.RGBA) *raster {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	return &raster{img: img}
+}
+
+func (r *raster) rect(x0, y0, x1, y1 int, c color.RGBA) {
+	if x1 < x0 {
+		x0, x1 = x1, x0
+	}
+	if y1 < y0 {
+		y0, y1 = y1, y0
+	}
+	rc := image.Rect(x0, y0, x1+1, y1+1).Intersect(r.img.Rect)
+	draw.Draw(r.img, rc, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// line steps along the longer axis and stamps a width×width square at
+// each step; chart lines are short and opaque, so no anti-aliasing.
+func (r *raster) line(x0, y0, x1, y1, width int, c color.RGBA) {
+	width = max(1, width)
+	half := (width - 1) / 2
+	dx, dy := x1-x0, y1-y0
+	steps := max(abs(dx), abs(dy))
+	for i := 0; i <= steps; i++ {
+		x, y := x0, y0
+		if steps > 0 {
+			x += (dx*i + sign(dx)*steps/2) / steps
+			y += (dy*i + sign(dy)*steps/2) / steps
+		}
+		r.rect(x-half, y-half, x-half+width-1, y-half+width-1, c)
+	}
+}
+
+func (r *raster) text(x, y int, s string, c color.RGBA) {
+	d := font.Drawer{
+		Dst:  r.img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(s)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
@@ -1,93 +1,54 @@
 package chart
 
-import (
-	"strings"
-
-	"github.com/guptarohit/asciigraph"
-)
-
+// RenderLineASCII draws closes as a single line with a labelled y-axis,
+// one column per close (most recent ones if they don't all fit).
 func RenderLineASCII(closes []float64, width, height int, header, caption, footer string) string {
-	if width <= 0 { width = 100 }
-	if height <= 0 { height = 30 }
-	chartW := max(40, width-4)
-	chartH := max(10, height-8)
-
-	graph := asciigraph.Plot(
-		closes,
-		asciigraph.Width(chartW),
-		asciigraph.Height(chartH),
-		asciigraph.Caption(caption),
-		asciigraph.Offset(1),
-		asciigraph.SeriesColors(asciigraph.Green),
-	)
-	var b strings.Builder
-	b.WriteString(header + "\n")
-	b.WriteString(graph)
-	b.WriteString("\n" + footer + "\n")
-	return b.String()
+	a := ASCIIChart{View: ViewLine, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	r := rendererPool.Get().(*Renderer)
+	defer rendererPool.Put(r)
+	r.out, r.marks = r.out[:0], r.marks[:0]
+	r.line(a, closes, nil)
+	return string(r.out)
 }
 
-
+// RenderCandlesASCII draws one candlestick per column (most recent ones if
+// they don't all fit).
 func RenderCandlesASCII(ticks []Tick, width, height int, header, caption, footer string) string {
-	if width <= 0 { width = 100 }
-	if height <= 0 { height = 30 }
-	chartW := max(50, width-4)
-	chartH := max(12, height-8)
+	a := ASCIIChart{View: ViewCandles, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	return a.Render(ticks)
+}
 
-	// one column per tick (use most recent if narrow)
-	if len(ticks) > chartW {
-		ticks = ticks[len(ticks)-chartW:]
-	}
+// RenderHeikinAshiASCII draws Heikin-Ashi candles derived from ticks.
+func RenderHeikinAshiASCII(ticks []Tick, width, height int, header, caption, footer string) string {
+	a := ASCIIChart{View: ViewHeikinAshi, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	return a.Render(ticks)
+}
 
-	lo, hi := ticks[0].L, ticks[0].H
-	for _, k := range ticks {
-		if k.L < lo { lo = k.L }
-		if k.H > hi { hi = k.H }
-	}
-	if hi == lo { hi = lo + 1 }
+// RenderOHLCBarsASCII draws classic OHLC bars: a vertical high–low range
+// with the open ticked to the left (┤) and the close to the right (├).
+func RenderOHLCBarsASCII(ticks []Tick, width, height int, header, caption, footer string) string {
+	a := ASCIIChart{View: ViewOHLC, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	return a.Render(ticks)
+}
 
-	// canvas rows: top→bottom; cols: left→right
-	canvas := make([][]rune, chartH)
-	for i := range canvas {
-		row := make([]rune, len(ticks))
-		for j := range row { row[j] = ' ' }
-		canvas[i] = row
-	}
-	yScale := func(p float64) int {
-		rel := (p - lo) / (hi - lo)
-		y := int(float64(chartH-1) - rel*float64(chartH-1))
-		if y < 0 { y = 0 }
-		if y >= chartH { y = chartH-1 }
-		return y
-	}
+// RenderRenkoASCII draws Renko bricks of the given size (0 = AutoBoxSize),
+// one brick per column.
+func RenderRenkoASCII(ticks []Tick, box float64, width, height int, header, caption, footer string) string {
+	a := ASCIIChart{View: ViewRenko, Box: box, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	return a.Render(ticks)
+}
 
-	upCol := make([]bool, len(ticks))
-	for x, k := range ticks {
-		yH, yL := yScale(k.H), yScale(k.L)
-		yO, yC := yScale(k.O), yScale(k.C)
-		for y := yH; y <= yL; y++ { canvas[y][x] = '│' } // wick
-		top, bot := yO, yC
-		if bot < top { top, bot = bot, top }
-		for y := top; y <= bot; y++ { canvas[y][x] = '█' } // body
-		upCol[x] = k.C >= k.O
-	}
+// RenderPointFigureASCII draws Point-and-Figure columns of X (rising) and
+// O (falling) boxes. box = 0 uses AutoBoxSize; reversal defaults to 3.
+func RenderPointFigureASCII(ticks []Tick, box float64, reversal int, width, height int, header, caption, footer string) string {
+	a := ASCIIChart{View: ViewPointFigure, Box: box, Reversal: reversal, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	return a.Render(ticks)
+}
 
-	var b strings.Builder
-	b.WriteString(header + "\n")
-	b.WriteString(caption + "\n")
-	for y := 0; y < chartH; y++ {
-		for x := 0; x < len(ticks); x++ {
-			r := canvas[y][x]
-			if r == '█' || r == '│' {
-				if upCol[x] { b.WriteString("\x1b[32m") } else { b.WriteString("\x1b[31m") }
-				b.WriteRune(r)
-				b.WriteString("\x1b[0m")
-			} else {
-				b.WriteByte(' ')
-			}
-		}
-		b.WriteByte('\n')
-	}
-	b.WriteString("\n" + footer + "\n")
-	return b.String()
+// RenderKagiASCII draws Kagi legs: thick (┃, up color) while yang, thin
+// (│, down color) while yin, joined by horizontal shoulders and waists.
+// reversal = 0 uses AutoBoxSize.
+func RenderKagiASCII(ticks []Tick, reversal float64, width, height int, header, caption, footer string) string {
+	a := ASCIIChart{View: ViewKagi, Box: reversal, Width: width, Height: height, Header: header, Caption: caption, Footer: footer, Cursor: -1}
+	return a.Render(ticks)
 }
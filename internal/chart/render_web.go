@@ -3,23 +3,253 @@ package chart
 import (
 	"bytes"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
 
+	"ticker-forge/internal/theme"
+
 	"github.com/go-echarts/go-echarts/v2/charts"
 	"github.com/go-echarts/go-echarts/v2/opts"
 )
 
+var webTheme = theme.MustLookup(theme.Default)
+
+// UseWebTheme sets the theme the echarts pages take their colors from.
+// Call it once at startup, before serving.
+func UseWebTheme(t theme.Theme) {
+	webTheme = t
+}
+
+// candleStyle colors K-line bodies and borders with the web theme
+// (echarts calls rising candles "color" and falling ones "color0").
+func candleStyle() charts.SeriesOpts {
+	up, down := theme.CSS(webTheme.Up), theme.CSS(webTheme.Down)
+	return charts.WithItemStyleOpts(opts.ItemStyle{Color: up, Color0: down, BorderColor: up, BorderColor0: down})
+}
+
+// valueAxis returns the price axis for scale: echarts' log axis for
+// ScaleLog, and a "%"-suffixed linear axis for ScalePercent (the data is
+// rebased by the caller).
+func valueAxis(scale Scale) opts.YAxis {
+	switch scale {
+	case ScaleLog:
+		return opts.YAxis{Type: "log", Scale: opts.Bool(true)}
+	case ScalePercent:
+		return opts.YAxis{Type: "value", Scale: opts.Bool(true), AxisLabel: &opts.AxisLabel{Formatter: "{value}%"}}
+	}
+	return opts.YAxis{Type: "value", Scale: opts.Bool(true)}
+}
+
+// rebase converts ticks to percent change from the first close when scale
+// is ScalePercent and returns them unchanged otherwise.
+func rebase(ticks []Tick, scale Scale) []Tick {
+	if scale != ScalePercent || len(ticks) == 0 {
+		return ticks
+	}
+	return PercentTicks(ticks, ticks[0].C)
+}
+
+// Overlay is what the web pages draw over the price series.
+type Overlay struct {
+	Annotations []Annotation
+	Drawings    []Drawing
+	Patterns    []PatternMatch
+	Levels      []Level
+	Zones       []Zone
+
+	// Profile adds a volume or TPO profile beside the candles; only
+	// RenderKlinePage draws it.
+	Profile ProfileMode
+}
+
+// percentOf returns the function that maps a price onto the chart's y
+// axis: percent change from base under ScalePercent, the price otherwise.
+func percentOf(scale Scale, base float64) func(float64) float64 {
+	if scale != ScalePercent || base == 0 {
+		return func(p float64) float64 { return p }
+	}
+	return func(p float64) float64 { return (p/base - 1) * 100 }
+}
+
+// seriesOpts returns the markers for ov on a series with x labels x drawn
+// from ticks (already rebased), with y mapping raw prices onto the axis.
+func (ov Overlay) seriesOpts(x []string, ticks []Tick, y func(float64) float64) []charts.SeriesOpts {
+	var points []opts.MarkPointNameCoordItem
+	var lines []opts.MarkLineNameXAxisItem
+	for _, a := range ov.Annotations {
+		i, ok := BarAt(ticks, a.Time)
+		if !ok {
+			continue
+		}
+		switch a.Kind {
+		case KindEarnings, KindDividend:
+			lines = append(lines, opts.MarkLineNameXAxisItem{Name: a.String(), XAxis: x[i]})
+			continue
+		}
+		_, _, above := a.Kind.glyph()
+		color, tag := theme.CSS(webTheme.Accent), "N"
+		switch a.Kind {
+		case KindBuy:
+			color, tag = theme.CSS(webTheme.Up), "B"
+		case KindSell:
+			color, tag = theme.CSS(webTheme.Down), "S"
+		}
+		at, rotate := ticks[i].H, float32(0)
+		if !above {
+			at, rotate = ticks[i].L, 180 // the pin points up at the bar
+		}
+		if a.Price != 0 {
+			at = y(a.Price)
+		}
+		points = append(points, opts.MarkPointNameCoordItem{
+			Name:         a.String(),
+			Coordinate:   []any{x[i], at},
+			Value:        tag,
+			Symbol:       "pin",
+			SymbolSize:   30,
+			SymbolRotate: rotate,
+			ItemStyle:    &opts.ItemStyle{Color: color},
+			Label:        &opts.Label{Show: opts.Bool(true), Formatter: "{c}"},
+		})
+	}
+
+	for _, m := range ov.Patterns {
+		i, ok := BarAt(ticks, m.Time)
+		if !ok {
+			continue
+		}
+		// as in the terminal: bullish points up from below, bearish down
+		// from above
+		color, at, symbol, rotate, pos := theme.CSS(webTheme.Subtle), ticks[i].H, "diamond", float32(0), "top"
+		switch m.Pattern.Bias() {
+		case 1:
+			color, at, symbol, pos = theme.CSS(webTheme.Up), ticks[i].L, "triangle", "bottom"
+		case -1:
+			color, symbol, rotate = theme.CSS(webTheme.Down), "triangle", 180
+		}
+		points = append(points, opts.MarkPointNameCoordItem{
+			Name:         string(m.Pattern),
+			Coordinate:   []any{x[i], at},
+			Value:        m.Pattern.Short(),
+			Symbol:       symbol,
+			SymbolSize:   8,
+			SymbolRotate: rotate,
+			ItemStyle:    &opts.ItemStyle{Color: color},
+			Label:        &opts.Label{Show: opts.Bool(true), Formatter: "{c}", Position: pos, Color: color},
+		})
+	}
+
+	levels, segments := ov.drawingLines(x, ticks, y)
+	for _, l := range ov.Levels {
+		levels = append(levels, opts.MarkLineNameYAxisItem{
+			Name:  l.Name + " " + strconv.FormatFloat(l.Price, 'f', 2, 64),
+			YAxis: y(l.Price),
+		})
+	}
+
+	var out []charts.SeriesOpts
+	if len(points) > 0 {
+		out = append(out, charts.WithMarkPointNameCoordItemOpts(points...))
+	}
+	if len(ov.Zones) > 0 && len(ticks) > 0 {
+		last := ticks[len(ticks)-1].C // rebased like the axis
+		var areas []opts.MarkAreaNameCoordItem
+		for _, z := range ov.Zones {
+			color := theme.CSS(webTheme.Down)
+			if y(z.Mid()) < last {
+				color = theme.CSS(webTheme.Up)
+			}
+			areas = append(areas, opts.MarkAreaNameCoordItem{
+				Name:        fmt.Sprintf("%d touches", z.Touches),
+				Coordinate0: []any{x[0], y(z.Hi)},
+				Coordinate1: []any{x[len(x)-1], y(z.Lo)},
+				ItemStyle:   &opts.ItemStyle{Color: color, Opacity: opts.Float(0.15)},
+			})
+		}
+		out = append(out,
+			charts.WithMarkAreaNameCoordItemOpts(areas...),
+			charts.WithMarkAreaStyleOpts(opts.MarkAreaStyle{
+				Label: &opts.Label{Show: opts.Bool(true), Position: "insideRight", Formatter: "{b}"},
+			}))
+	}
+	if len(lines)+len(levels)+len(segments) > 0 {
+		out = append(out,
+			charts.WithMarkLineNameXAxisItemOpts(lines...),
+			charts.WithMarkLineNameYAxisItemOpts(levels...),
+			charts.WithMarkLineNameCoordItemOpts(segments...),
+			charts.WithMarkLineStyleOpts(opts.MarkLineStyle{
+				Symbol:    []string{"none", "none"},
+				Label:     &opts.Label{Show: opts.Bool(true), Formatter: "{b}"},
+				LineStyle: &opts.LineStyle{Type: "dashed", Color: theme.CSS(webTheme.Accent)},
+			}))
+	}
+	return out
+}
+
+// drawingLines turns ov's drawings into echarts mark lines: levels span
+// the chart, trendlines run from their first point to the last bar and
+// retracements from the start of their move to the last bar.
+func (ov Overlay) drawingLines(x []string, ticks []Tick, y func(float64) float64) ([]opts.MarkLineNameYAxisItem, []opts.MarkLineNameCoordItem) {
+	var levels []opts.MarkLineNameYAxisItem
+	var segments []opts.MarkLineNameCoordItem
+	last := len(ticks) - 1
+	for _, d := range ov.Drawings {
+		if d.Kind == DrawLevel {
+			levels = append(levels, opts.MarkLineNameYAxisItem{Name: d.String(), YAxis: y(d.From.Price)})
+			continue
+		}
+		from, ok := BarAt(ticks, d.From.Time)
+		if !ok {
+			if len(ticks) == 0 || d.From.Time.After(ticks[last].T) {
+				continue
+			}
+			from = 0 // starts before the data: clip to the first bar
+		}
+		switch d.Kind {
+		case DrawTrend:
+			p0, ok0 := d.TrendAt(ticks[from].T)
+			p1, ok1 := d.TrendAt(ticks[last].T)
+			if !ok0 || !ok1 {
+				continue
+			}
+			segments = append(segments, opts.MarkLineNameCoordItem{
+				Name:        d.String(),
+				Coordinate0: []any{x[from], y(p0)},
+				Coordinate1: []any{x[last], y(p1)},
+			})
+		case DrawFib:
+			for _, r := range FibRatios {
+				p := d.FibLevel(r)
+				segments = append(segments, opts.MarkLineNameCoordItem{
+					Name:        fmt.Sprintf("%.1f%%  %.2f", r*100, p),
+					Coordinate0: []any{x[from], y(p)},
+					Coordinate1: []any{x[last], y(p)},
+				})
+			}
+		}
+	}
+	return levels, segments
+}
+
 // RenderLinePage renders a simple line chart of closes over time.
-func RenderLinePage(symbol string, times []time.Time, closes []float64) ([]byte, error) {
+func RenderLinePage(symbol string, times []time.Time, closes []float64, scale Scale, ov Overlay) ([]byte, error) {
 	if len(times) != len(closes) || len(closes) == 0 {
 		return nil, fmt.Errorf("RenderLinePage: mismatched/empty data")
 	}
+	price := percentOf(scale, closes[0])
+	if scale == ScalePercent {
+		closes = PercentCloses(closes)
+	}
 
 	x := make([]string, 0, len(times))
 	y := make([]opts.LineData, 0, len(closes))
+	points := make([]Tick, 0, len(closes)) // for placing the overlay
 	for i, t := range times {
 		x = append(x, t.Format("2006-01-02 15:04"))
 		y = append(y, opts.LineData{Value: closes[i]})
+		points = append(points, Tick{T: t, O: closes[i], H: closes[i], L: closes[i], C: closes[i]})
 	}
 
 	line := charts.NewLine()
@@ -37,13 +267,74 @@ func RenderLinePage(symbol string, times []time.Time, closes []float64) ([]byte,
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
 		charts.WithDataZoomOpts(opts.DataZoom{Type: "inside", Start: 0, End: 100}),
 		charts.WithXAxisOpts(opts.XAxis{Type: "category"}),
-		charts.WithYAxisOpts(opts.YAxis{Type: "value", Scale: opts.Bool(true)}),
+		charts.WithYAxisOpts(valueAxis(scale)),
 	)
 	line.SetXAxis(x).AddSeries("Close", y).
-		SetSeriesOptions(
+		SetSeriesOptions(append([]charts.SeriesOpts{
 			charts.WithLineChartOpts(opts.LineChart{Smooth: opts.Bool(true)}),
+			charts.WithLineStyleOpts(opts.LineStyle{Color: theme.CSS(webTheme.Up)}),
 			charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: opts.Float(0.15)}),
-		)
+		}, ov.seriesOpts(x, points, price)...)...)
+
+	var buf bytes.Buffer
+	if err := line.Render(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderComparePage renders several series (see CompareSeries) as one line
+// each with a legend. The y-axis reads as % change from the first shared
+// bar; on a log scale the indexed values are plotted and relabelled.
+func RenderComparePage(series []Series, scale Scale) ([]byte, error) {
+	if len(series) == 0 || len(series[0].Ticks) == 0 {
+		return nil, fmt.Errorf("RenderComparePage: empty data")
+	}
+
+	names := make([]string, len(series))
+	for i, s := range series {
+		names[i] = s.Name
+	}
+	x := make([]string, 0, len(series[0].Ticks))
+	for _, t := range compareTimes(series) {
+		x = append(x, t.Format("2006-01-02 15:04"))
+	}
+	yAxis := opts.YAxis{Type: "value", Scale: opts.Bool(true), AxisLabel: &opts.AxisLabel{Formatter: "{value}%"}}
+	offset := 100.0
+	if scale == ScaleLog {
+		yAxis = opts.YAxis{Type: "log", Scale: opts.Bool(true), AxisLabel: &opts.AxisLabel{
+			Formatter: opts.FuncOpts("function (v) { return (v - 100).toFixed(1) + '%'; }"),
+		}}
+		offset = 0
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			PageTitle: fmt.Sprintf("%s · Compare", strings.Join(names, ", ")),
+			Width:     "100%",
+			Height:    "560px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    strings.Join(names, " vs "),
+			Subtitle: "% change from first shared bar · Data: Yahoo Finance (unofficial)",
+			Left:     "center",
+		}),
+		charts.WithLegendOpts(opts.Legend{Show: opts.Bool(true), Top: "52"}),
+		charts.WithColorsOpts(webTheme.CSSSeries()),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithDataZoomOpts(opts.DataZoom{Type: "inside", Start: 0, End: 100}),
+		charts.WithXAxisOpts(opts.XAxis{Type: "category"}),
+		charts.WithYAxisOpts(yAxis),
+	)
+	line.SetXAxis(x)
+	for _, s := range series {
+		y := make([]opts.LineData, 0, len(s.Ticks))
+		for _, k := range s.Ticks {
+			y = append(y, opts.LineData{Value: k.C - offset})
+		}
+		line.AddSeries(s.Name, y, charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)}))
+	}
 
 	var buf bytes.Buffer
 	if err := line.Render(&buf); err != nil {
@@ -54,10 +345,12 @@ func RenderLinePage(symbol string, times []time.Time, closes []float64) ([]byte,
 
 // RenderKlinePage renders OHLC candles (K-line).
 // Uses chart.Tick from your chart package (T, O, H, L, C, V).
-func RenderKlinePage(symbol string, ticks []Tick) ([]byte, error) {
+func RenderKlinePage(symbol string, ticks []Tick, scale Scale, ov Overlay) ([]byte, error) {
 	if len(ticks) == 0 {
 		return nil, fmt.Errorf("RenderKlinePage: empty data")
 	}
+	price := percentOf(scale, ticks[0].C)
+	ticks = rebase(ticks, scale)
 
 	x := make([]string, 0, len(ticks))
 	y := make([]opts.KlineData, 0, len(ticks))
@@ -82,8 +375,12 @@ func RenderKlinePage(symbol string, ticks []Tick) ([]byte, error) {
 		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
 		charts.WithDataZoomOpts(opts.DataZoom{Type: "inside", Start: 0, End: 100}),
 		charts.WithXAxisOpts(opts.XAxis{Type: "category"}),
+		charts.WithYAxisOpts(valueAxis(scale)),
 	)
-	k.SetXAxis(x).AddSeries("kline", y)
+	k.SetXAxis(x).AddSeries("kline", y, append([]charts.SeriesOpts{candleStyle()}, ov.seriesOpts(x, ticks, price)...)...)
+	if ov.Profile != ProfileNone {
+		withProfile(k, ticks, ov.Profile, scale)
+	}
 
 	var buf bytes.Buffer
 	if err := k.Render(&buf); err != nil {
@@ -91,3 +388,267 @@ func RenderKlinePage(symbol string, ticks []Tick) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// klinePage renders a K-line page with the shared title/tooltip/zoom setup;
+// the series-specific pages below only differ in the data they feed it.
+func klinePage(symbol, label string, scale Scale, x []string, y []opts.KlineData, seriesOpts ...charts.SeriesOpts) ([]byte, error) {
+	k := charts.NewKLine()
+	k.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			PageTitle: fmt.Sprintf("%s · %s", symbol, label),
+			Width:     "100%",
+			Height:    "560px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    fmt.Sprintf("%s – %s", symbol, label),
+			Subtitle: "Data: Yahoo Finance (unofficial)",
+			Left:     "center",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "axis"}),
+		charts.WithDataZoomOpts(opts.DataZoom{Type: "inside", Start: 0, End: 100}),
+		charts.WithXAxisOpts(opts.XAxis{Type: "category"}),
+		charts.WithYAxisOpts(valueAxis(scale)),
+	)
+	k.SetXAxis(x).AddSeries(label, y, append([]charts.SeriesOpts{candleStyle()}, seriesOpts...)...)
+
+	var buf bytes.Buffer
+	if err := k.Render(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// profileBins is how many price slices the web profile has.
+const profileBins = 48
+
+// withProfile adds the profile of ticks (already rebased) to k as
+// horizontal bars in a grid of their own to the right of the candles. The
+// bars sit on a category axis of price slices and the candles' axis is
+// pinned to the same range, so each bar lines up with its prices; under
+// ScaleLog the slices are cut in log space to match.
+func withProfile(k *charts.Kline, ticks []Tick, mode ProfileMode, scale Scale) {
+	price := func(v float64) float64 { return v }
+	if scale == ScaleLog {
+		logged := make([]Tick, len(ticks))
+		for i, t := range ticks {
+			logged[i] = t
+			logged[i].L, logged[i].H = math.Log(t.L), math.Log(t.H)
+		}
+		ticks, price = logged, math.Exp
+	}
+	p := newProfile(ticks, profileBins, mode)
+	if p.Counts == nil {
+		return
+	}
+
+	suffix := ""
+	if scale == ScalePercent {
+		suffix = "%"
+	}
+	labels := make([]string, len(p.Counts))
+	bars := make([]opts.BarData, len(p.Counts))
+	for i, n := range p.Counts {
+		labels[i] = strconv.FormatFloat(price(p.Price(i)), 'f', 2, 64) + suffix
+		style := &opts.ItemStyle{Color: theme.CSS(webTheme.Subtle), Opacity: opts.Float(0.35)}
+		switch {
+		case i == p.POC:
+			style = &opts.ItemStyle{Color: theme.CSS(webTheme.Accent)}
+		case i >= p.VAL && i <= p.VAH:
+			style.Opacity = opts.Float(0.8)
+		}
+		bars[i] = opts.BarData{Value: n, ItemStyle: style}
+		if p.Letters != nil {
+			bars[i].Name = p.Letters[i]
+		}
+	}
+
+	axis := valueAxis(scale)
+	axis.Scale = nil
+	axis.Min, axis.Max = price(p.Lo), price(p.Lo+p.Step*float64(len(p.Counts)))
+	k.SetGlobalOptions(
+		charts.WithYAxisOpts(axis),
+		charts.WithGridOpts(
+			opts.Grid{Left: "6%", Right: "24%", Top: "80", Bottom: "60"},
+			opts.Grid{Left: "78%", Right: "6%", Top: "80", Bottom: "60"},
+		),
+	)
+	k.ExtendXAxis(opts.XAxis{
+		Type:      "value",
+		GridIndex: 1,
+		Name:      mode.String(),
+		AxisLabel: &opts.AxisLabel{Show: opts.Bool(false)},
+		SplitLine: &opts.SplitLine{Show: opts.Bool(false)},
+	})
+	k.ExtendYAxis(opts.YAxis{
+		Type:      "category",
+		GridIndex: 1,
+		Position:  "right",
+		Data:      labels,
+	})
+
+	bar := charts.NewBar()
+	bar.AddSeries(mode.String()+" profile", bars,
+		charts.WithBarChartOpts(opts.BarChart{XAxisIndex: 1, YAxisIndex: 1, BarCategoryGap: "10%"}))
+	k.Overlap(bar)
+}
+
+// tickKline converts ticks to K-line x labels and [open, close, low, high] values.
+func tickKline(ticks []Tick) ([]string, []opts.KlineData) {
+	x := make([]string, 0, len(ticks))
+	y := make([]opts.KlineData, 0, len(ticks))
+	for _, k := range ticks {
+		x = append(x, k.T.Format("2006-01-02 15:04"))
+		y = append(y, opts.KlineData{Value: []any{k.O, k.C, k.L, k.H}})
+	}
+	return x, y
+}
+
+// RenderHeikinAshiPage renders Heikin-Ashi candles derived from ticks.
+func RenderHeikinAshiPage(symbol string, ticks []Tick, scale Scale, ov Overlay) ([]byte, error) {
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("RenderHeikinAshiPage: empty data")
+	}
+	price := percentOf(scale, ticks[0].C)
+	ha := HeikinAshi(rebase(ticks, scale))
+	x, y := tickKline(ha)
+	return klinePage(symbol, "Heikin-Ashi", scale, x, y, ov.seriesOpts(x, ha, price)...)
+}
+
+// RenderOHLCPage renders OHLC bars. ECharts has no native OHLC bar series,
+// so they are drawn as hollow, narrow candles.
+func RenderOHLCPage(symbol string, ticks []Tick, scale Scale, ov Overlay) ([]byte, error) {
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("RenderOHLCPage: empty data")
+	}
+	price := percentOf(scale, ticks[0].C)
+	ticks = rebase(ticks, scale)
+	x, y := tickKline(ticks)
+	return klinePage(symbol, "OHLC", scale, x, y, append([]charts.SeriesOpts{
+		charts.WithKlineChartOpts(opts.KlineChart{BarMaxWidth: "3"}),
+		charts.WithItemStyleOpts(opts.ItemStyle{
+			Color:        "transparent",
+			Color0:       "transparent",
+			BorderColor:  theme.CSS(webTheme.Up),
+			BorderColor0: theme.CSS(webTheme.Down),
+			BorderWidth:  1.5,
+		}),
+	}, ov.seriesOpts(x, ticks, price)...)...)
+}
+
+// RenderRenkoPage renders Renko bricks of the given size (0 = AutoBoxSize).
+func RenderRenkoPage(symbol string, ticks []Tick, box float64, scale Scale) ([]byte, error) {
+	ticks = rebase(ticks, scale)
+	if box <= 0 {
+		box = AutoBoxSize(ticks)
+	}
+	bricks, truncated := renko(ticks, box)
+	if len(bricks) == 0 {
+		return nil, fmt.Errorf("RenderRenkoPage: no bricks for box size %.4g", box)
+	}
+	title := fmt.Sprintf("Renko (box %.4g)", box)
+	if truncated {
+		title += ", " + renkoNote
+	}
+	x, y := tickKline(bricks)
+	return klinePage(symbol, title, scale, x, y)
+}
+
+// RenderPointFigurePage renders Point-and-Figure columns as K-line bodies
+// spanning each column's boxes (rising X columns up, falling O columns down).
+func RenderPointFigurePage(symbol string, ticks []Tick, box float64, reversal int, scale Scale) ([]byte, error) {
+	ticks = rebase(ticks, scale)
+	if box <= 0 {
+		box = AutoBoxSize(ticks)
+	}
+	cols := PointFigure(ticks, box, reversal)
+	if len(cols) == 0 {
+		return nil, fmt.Errorf("RenderPointFigurePage: no columns for box size %.4g", box)
+	}
+	x := make([]string, 0, len(cols))
+	y := make([]opts.KlineData, 0, len(cols))
+	for _, c := range cols {
+		x = append(x, c.T.Format("2006-01-02 15:04"))
+		lo, hi := c.Low-box/2, c.High+box/2
+		if c.Up {
+			y = append(y, opts.KlineData{Value: []any{lo, hi, lo, hi}})
+		} else {
+			y = append(y, opts.KlineData{Value: []any{hi, lo, lo, hi}})
+		}
+	}
+	return klinePage(symbol, fmt.Sprintf("Point & Figure (box %.4g)", box), scale, x, y)
+}
+
+// RenderKagiPage renders Kagi legs on a value x-axis (one unit per leg).
+// Yang and yin stretches are separate series so they get their own widths.
+func RenderKagiPage(symbol string, ticks []Tick, reversal float64, scale Scale) ([]byte, error) {
+	ticks = rebase(ticks, scale)
+	if reversal <= 0 {
+		reversal = AutoBoxSize(ticks)
+	}
+	legs := Kagi(ticks, reversal)
+	if len(legs) == 0 {
+		return nil, fmt.Errorf("RenderKagiPage: price never reversed by %.4g", reversal)
+	}
+
+	var yang, yin []opts.LineData
+	gap := func(s []opts.LineData) []opts.LineData {
+		if len(s) > 0 && s[len(s)-1].Value != "-" {
+			s = append(s, opts.LineData{Value: "-"})
+		}
+		return s
+	}
+	add := func(isYang bool, pts ...[]any) {
+		for _, p := range pts {
+			d := opts.LineData{Value: p}
+			if isYang {
+				yang = append(yang, d)
+				yin = gap(yin)
+			} else {
+				yin = append(yin, d)
+				yang = gap(yang)
+			}
+		}
+	}
+	for i, l := range legs {
+		mid := l.To
+		if l.Shifted {
+			mid = l.Shift
+		}
+		add(l.Yang, []any{i, l.From}, []any{i, mid})
+		if l.Shifted {
+			add(!l.Yang, []any{i, mid}, []any{i, l.To})
+		}
+		if i < len(legs)-1 {
+			add(l.YangAtEnd(), []any{i, l.To}, []any{i + 1, l.To})
+		}
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			PageTitle: fmt.Sprintf("%s · Kagi", symbol),
+			Width:     "100%",
+			Height:    "560px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    fmt.Sprintf("%s – Kagi (reversal %.4g)", symbol, reversal),
+			Subtitle: "Data: Yahoo Finance (unofficial)",
+			Left:     "center",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{Show: opts.Bool(true), Trigger: "item"}),
+		charts.WithDataZoomOpts(opts.DataZoom{Type: "inside", Start: 0, End: 100}),
+		charts.WithXAxisOpts(opts.XAxis{Type: "value", Show: opts.Bool(false)}),
+		charts.WithYAxisOpts(valueAxis(scale)),
+	)
+	lineOpts := charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)})
+	line.AddSeries("Yang", yang, lineOpts,
+		charts.WithLineStyleOpts(opts.LineStyle{Color: theme.CSS(webTheme.Up), Width: 3}))
+	line.AddSeries("Yin", yin, lineOpts,
+		charts.WithLineStyleOpts(opts.LineStyle{Color: theme.CSS(webTheme.Down), Width: 1}))
+
+	var buf bytes.Buffer
+	if err := line.Render(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,70 @@
+package chart
+
+import "strings"
+
+// Scale selects how prices map onto the vertical axis.
+type Scale int
+
+const (
+	ScaleLinear  Scale = iota
+	ScaleLog           // equal distances are equal ratios
+	ScalePercent       // linear, labelled as % change from the first bar
+)
+
+var scaleNames = [...]string{
+	ScaleLinear:  "linear",
+	ScaleLog:     "log",
+	ScalePercent: "percent",
+}
+
+// String returns the name used for the scale in query strings and captions.
+func (s Scale) String() string {
+	if s < 0 || int(s) >= len(scaleNames) {
+		return "unknown"
+	}
+	return scaleNames[s]
+}
+
+// Next returns the scale after s, wrapping back to ScaleLinear.
+func (s Scale) Next() Scale {
+	return Scale((int(s) + 1) % len(scaleNames))
+}
+
+// ParseScale maps a scale name (plus "lin", "pct" and "%") back to its Scale.
+func ParseScale(s string) (Scale, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "linear", "lin":
+		return ScaleLinear, true
+	case "log", "logarithmic":
+		return ScaleLog, true
+	case "percent", "pct", "%":
+		return ScalePercent, true
+	}
+	return ScaleLinear, false
+}
+
+// PercentTicks rebases ticks to percent change from base (base → 0%).
+// Volume and times are kept as-is.
+func PercentTicks(ticks []Tick, base float64) []Tick {
+	if base == 0 {
+		return ticks
+	}
+	pct := func(p float64) float64 { return (p/base - 1) * 100 }
+	out := make([]Tick, len(ticks))
+	for i, k := range ticks {
+		out[i] = Tick{T: k.T, O: pct(k.O), H: pct(k.H), L: pct(k.L), C: pct(k.C), V: k.V}
+	}
+	return out
+}
+
+// PercentCloses rebases closes to percent change from the first one.
+func PercentCloses(closes []float64) []float64 {
+	if len(closes) == 0 || closes[0] == 0 {
+		return closes
+	}
+	out := make([]float64, len(closes))
+	for i, c := range closes {
+		out[i] = (c/closes[0] - 1) * 100
+	}
+	return out
+}
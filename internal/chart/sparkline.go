@@ -0,0 +1,198 @@
+package chart
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+
+	"ticker-forge/internal/theme"
+)
+
+// SparkStyle picks the glyphs a Sparkline is drawn with.
+type SparkStyle int
+
+const (
+	// SparkBlocks draws one column per cell with the eighth blocks ▁…█.
+	SparkBlocks SparkStyle = iota
+	// SparkBraille draws two columns per cell, four dots high, as a line.
+	SparkBraille
+)
+
+// Sparkline describes a one-row chart of a series, e.g. a watchlist row.
+type Sparkline struct {
+	Style SparkStyle
+	Width int // cells; 0 means 20
+
+	// Color draws the whole line in the up or down color depending on
+	// whether the last value is at or above Base (the previous close), or
+	// above the first value when Base is 0.
+	Color   bool
+	Base    float64
+	Palette *theme.Palette // nil uses the default theme for stdout
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Render draws values in exactly Width cells, stretching or thinning the
+// series to fit. An empty series renders as blanks.
+func (s Sparkline) Render(values []float64) string {
+	width := s.Width
+	if width <= 0 {
+		width = 20
+	}
+	if len(values) == 0 {
+		return strings.Repeat(" ", width)
+	}
+
+	var body string
+	if s.Style == SparkBraille {
+		body = brailleLine(fit(values, 2*width), width)
+	} else {
+		body = blockLine(fit(values, width))
+	}
+	if !s.Color {
+		return body
+	}
+	pal := s.Palette
+	if pal == nil {
+		pal = defaultPalette()
+	}
+	seq := pal.Down
+	if sparkUp(values, s.Base) {
+		seq = pal.Up
+	}
+	if seq == "" {
+		return body
+	}
+	return seq + body + pal.Reset
+}
+
+// sparkUp reports whether the series ended at or above base, or above its
+// first value when base is 0.
+func sparkUp(values []float64, base float64) bool {
+	if base == 0 {
+		base = values[0]
+	}
+	return values[len(values)-1] >= base
+}
+
+// fit resamples values to n points, taking the last value of each bucket
+// when thinning and repeating values when stretching.
+func fit(values []float64, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = values[max(i*len(values)/n, (i+1)*len(values)/n-1)]
+	}
+	return out
+}
+
+// levels maps values onto 0..steps-1; a flat series sits in the middle
+// and values that aren't finite sit at the bottom.
+func levels(values []float64, steps int) []int {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			lo, hi = min(lo, v), max(hi, v)
+		}
+	}
+	out := make([]int, len(values))
+	for i, v := range values {
+		switch {
+		case hi == lo:
+			out[i] = steps / 2
+		case lo < hi && !math.IsNaN(v) && !math.IsInf(v, 0):
+			out[i] = int(math.Round((v - lo) / (hi - lo) * float64(steps-1)))
+		}
+	}
+	return out
+}
+
+func blockLine(values []float64) string {
+	var b strings.Builder
+	for _, l := range levels(values, len(sparkBlocks)) {
+		b.WriteRune(sparkBlocks[l])
+	}
+	return b.String()
+}
+
+// brailleDots[col][row] is the dot bit for the column (0 left, 1 right)
+// and row (0 top) of a Braille cell.
+var brailleDots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// brailleLine draws 2*width values as a connected line: each column gets a
+// dot at its level plus the dots between it and the previous column.
+func brailleLine(values []float64, width int) string {
+	lv := levels(values, 4)
+	var b strings.Builder
+	for c := 0; c < width; c++ {
+		r := rune(0x2800)
+		for col := 0; col < 2; col++ {
+			i := 2*c + col
+			from, to := lv[i], lv[i]
+			if i > 0 {
+				from = lv[i-1]
+			}
+			if from > to {
+				from, to = to, from
+			}
+			for l := from; l <= to; l++ {
+				r |= brailleDots[col][3-l] // level 3 is the top row
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SparklineSVG returns an inline SVG sparkline of values, width×height
+// pixels, stroked in the web theme's up or down color by the change from
+// the first to the last value. It is registered as the "sparkline"
+// template function.
+func SparklineSVG(values []float64, width, height int) template.HTML {
+	if width <= 0 {
+		width = 120
+	}
+	if height <= 0 {
+		height = 24
+	}
+	if len(values) == 0 {
+		return template.HTML(fmt.Sprintf(`<svg class="sparkline" width="%d" height="%d"></svg>`, width, height))
+	}
+	color := theme.CSS(webTheme.Down)
+	if sparkUp(values, 0) {
+		color = theme.CSS(webTheme.Up)
+	}
+
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	if hi == lo {
+		lo, hi = lo-1, hi+1
+	}
+	const pad = 2 // keeps the stroke and end dot inside the box
+	x := func(i int) float64 {
+		if len(values) == 1 {
+			return float64(width) / 2
+		}
+		return pad + float64(i)*float64(width-2*pad)/float64(len(values)-1)
+	}
+	y := func(v float64) float64 {
+		return pad + (hi-v)/(hi-lo)*float64(height-2*pad)
+	}
+
+	var pts strings.Builder
+	for i, v := range values {
+		fmt.Fprintf(&pts, "%.1f,%.1f ", x(i), y(v))
+	}
+	last := len(values) - 1
+	return template.HTML(fmt.Sprintf(
+		`<svg class="sparkline" xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+
+			`<polyline fill="none" stroke="%s" stroke-width="1.5" stroke-linejoin="round" points="%s"/>`+
+			`<circle cx="%.1f" cy="%.1f" r="2" fill="%s"/></svg>`,
+		width, height, width, height, color, strings.TrimSpace(pts.String()), x(last), y(values[last]), color))
+}
@@ -0,0 +1,181 @@
+// Package studies computes chart indicators from price series. Every
+// function returns one value per input, with math.NaN() where there is
+// not yet enough history (the first period-1 values).
+package studies
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SMA is the simple moving average over period values.
+func SMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period < 1 {
+		return out
+	}
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// EMA is the exponential moving average with smoothing 2/(period+1),
+// seeded with the SMA of the first period values.
+func EMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if period < 1 || len(values) < period {
+		return out
+	}
+	k := 2 / float64(period+1)
+	var seed float64
+	for _, v := range values[:period] {
+		seed += v
+	}
+	prev := seed / float64(period)
+	out[period-1] = prev
+	for i := period; i < len(values); i++ {
+		prev += k * (values[i] - prev)
+		out[i] = prev
+	}
+	return out
+}
+
+// Bollinger returns the period SMA and the bands k population standard
+// deviations above and below it.
+func Bollinger(values []float64, period int, k float64) (mid, upper, lower []float64) {
+	mid = SMA(values, period)
+	upper, lower = nans(len(values)), nans(len(values))
+	for i := period - 1; i < len(values) && period > 0; i++ {
+		var ss float64
+		for _, v := range values[i-period+1 : i+1] {
+			ss += (v - mid[i]) * (v - mid[i])
+		}
+		sd := math.Sqrt(ss / float64(period))
+		upper[i], lower[i] = mid[i]+k*sd, mid[i]-k*sd
+	}
+	return mid, upper, lower
+}
+
+func nans(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// Kind is a study drawn over the price chart.
+type Kind int
+
+const (
+	KindSMA Kind = iota
+	KindEMA
+	KindBollinger
+)
+
+var kindNames = [...]string{"sma", "ema", "bb"}
+
+func (k Kind) String() string {
+	if k < 0 || int(k) >= len(kindNames) {
+		return "sma"
+	}
+	return kindNames[k]
+}
+
+// Spec is one configured study, e.g. a 50-period EMA.
+type Spec struct {
+	Kind   Kind
+	Period int
+}
+
+// DefaultPeriod is used when a spec names no period ("sma").
+const DefaultPeriod = 20
+
+// String formats s the way ParseSpec reads it ("ema:50").
+func (s Spec) String() string {
+	return fmt.Sprintf("%s:%d", s.Kind, s.Period)
+}
+
+// Label is the legend text ("EMA 50").
+func (s Spec) Label() string {
+	return fmt.Sprintf("%s %d", strings.ToUpper(s.Kind.String()), s.Period)
+}
+
+// ParseSpec reads "sma:20", "ema 50", "bb" (period DefaultPeriod) and the
+// like; "bollinger" is accepted for "bb".
+func ParseSpec(s string) (Spec, error) {
+	name, period, _ := strings.Cut(strings.TrimSpace(s), ":")
+	if f := strings.Fields(name); len(f) == 2 && period == "" {
+		name, period = f[0], f[1]
+	}
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "bollinger" {
+		name = "bb"
+	}
+	spec := Spec{Period: DefaultPeriod}
+	found := false
+	for i, n := range kindNames {
+		if name == n {
+			spec.Kind, found = Kind(i), true
+		}
+	}
+	if !found {
+		return Spec{}, fmt.Errorf("unknown study %q (want sma, ema or bb)", name)
+	}
+	if period = strings.TrimSpace(period); period != "" {
+		n, err := strconv.Atoi(period)
+		if err != nil || n < 1 {
+			return Spec{}, fmt.Errorf("study %s: bad period %q", name, period)
+		}
+		spec.Period = n
+	}
+	return spec, nil
+}
+
+// ParseSpecs reads a comma-separated list of specs ("sma:20,ema:50").
+func ParseSpecs(s string) ([]Spec, error) {
+	var out []Spec
+	for _, f := range strings.Split(s, ",") {
+		if strings.TrimSpace(f) == "" {
+			continue
+		}
+		spec, err := ParseSpec(f)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, spec)
+	}
+	return out, nil
+}
+
+// Line is one drawn line of a study.
+type Line struct {
+	Name   string
+	Values []float64
+}
+
+// Apply computes s over closes. Bollinger bands yield three lines.
+func (s Spec) Apply(closes []float64) []Line {
+	switch s.Kind {
+	case KindEMA:
+		return []Line{{Name: s.Label(), Values: EMA(closes, s.Period)}}
+	case KindBollinger:
+		mid, upper, lower := Bollinger(closes, s.Period, 2)
+		return []Line{
+			{Name: s.Label(), Values: mid},
+			{Name: s.Label() + " upper", Values: upper},
+			{Name: s.Label() + " lower", Values: lower},
+		}
+	}
+	return []Line{{Name: s.Label(), Values: SMA(closes, s.Period)}}
+}
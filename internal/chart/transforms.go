@@ -0,0 +1,270 @@
+package chart
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// HeikinAshi converts regular candles into Heikin-Ashi candles.
+// HA close = (O+H+L+C)/4, HA open = midpoint of the previous HA body,
+// HA high/low = extremes of (H, L, HA open, HA close).
+func HeikinAshi(ticks []Tick) []Tick {
+	return appendHeikinAshi(make([]Tick, 0, len(ticks)), ticks)
+}
+
+// appendHeikinAshi appends the Heikin-Ashi candles of ticks to out, which
+// must be empty.
+func appendHeikinAshi(out, ticks []Tick) []Tick {
+	for i, k := range ticks {
+		c := (k.O + k.H + k.L + k.C) / 4
+		o := (k.O + k.C) / 2
+		if i > 0 {
+			p := out[i-1]
+			o = (p.O + p.C) / 2
+		}
+		out = append(out, Tick{
+			T: k.T,
+			O: o,
+			H: math.Max(k.H, math.Max(o, c)),
+			L: math.Min(k.L, math.Min(o, c)),
+			C: c,
+			V: k.V,
+		})
+	}
+	return out
+}
+
+// AutoBoxSize picks a brick/box size for Renko, Point-and-Figure and Kagi
+// from the average true range of the last 14 bars.
+func AutoBoxSize(ticks []Tick) float64 {
+	if len(ticks) == 0 {
+		return 1
+	}
+	start := len(ticks) - 14
+	if start < 1 {
+		start = 1
+	}
+	var sum float64
+	var n int
+	for i := start; i < len(ticks); i++ {
+		k, prev := ticks[i], ticks[i-1].C
+		tr := math.Max(k.H-k.L, math.Max(math.Abs(k.H-prev), math.Abs(k.L-prev)))
+		sum += tr
+		n++
+	}
+	if n > 0 && sum > 0 {
+		return sum / float64(n)
+	}
+	last := math.Abs(ticks[len(ticks)-1].C)
+	if last == 0 {
+		return 1
+	}
+	return last * 0.005
+}
+
+// maxBricks bounds Renko output: a gap of millions of boxes (a bad print,
+// a box far too small for the price) would otherwise exhaust memory.
+const maxBricks = 10000
+
+// Renko builds bricks of a fixed size from closing prices. A new brick is
+// added each time price moves one box beyond the last brick in the trend
+// direction, or two boxes against it. Each brick is returned as a Tick
+// whose O/C are the brick edges (H/L equal max/min of those). Only the
+// newest maxBricks are kept.
+func Renko(ticks []Tick, box float64) []Tick {
+	bricks, _ := renko(ticks, box)
+	return bricks
+}
+
+// renko is Renko, also reporting whether older bricks were dropped.
+func renko(ticks []Tick, box float64) (bricks []Tick, truncated bool) {
+	if len(ticks) == 0 || !validBox(box) {
+		return nil, false
+	}
+	var out []Tick
+	add := func(b Tick) {
+		out = append(out, b)
+		if len(out) == 2*maxBricks {
+			out, truncated = append(out[:0], out[maxBricks:]...), true
+		}
+	}
+	top, bottom := ticks[0].C, ticks[0].C
+	for _, k := range ticks[1:] {
+		// skip the bricks of a gap that would be dropped anyway
+		if n := math.Floor((k.C-top)/box) - maxBricks; n >= 1 {
+			top, truncated = top+n*box, true
+			bottom = top - box
+		}
+		if n := math.Floor((bottom-k.C)/box) - maxBricks; n >= 1 {
+			bottom, truncated = bottom-n*box, true
+			top = bottom + box
+		}
+		// top+box == top once box is below the float precision of top
+		for k.C >= top+box && top+box != top {
+			add(Tick{T: k.T, O: top, H: top + box, L: top, C: top + box})
+			bottom, top = top, top+box
+		}
+		for k.C <= bottom-box && bottom-box != bottom {
+			add(Tick{T: k.T, O: bottom, H: bottom, L: bottom - box, C: bottom - box})
+			top, bottom = bottom, bottom-box
+		}
+	}
+	if len(out) > maxBricks {
+		out, truncated = out[len(out)-maxBricks:], true
+	}
+	return out, truncated
+}
+
+// renkoNote is the caption note for a Renko chart missing its oldest
+// bricks.
+var renkoNote = fmt.Sprintf("newest %d bricks", maxBricks)
+
+// validBox reports whether box can size bricks: positive and finite.
+func validBox(box float64) bool {
+	return box > 0 && !math.IsInf(box, 1)
+}
+
+// PFColumn is one column of a Point-and-Figure chart: a run of X boxes
+// (rising) or O boxes (falling) between Low and High.
+type PFColumn struct {
+	T    time.Time // time the column was started
+	Up   bool      // X column when true, O column otherwise
+	Low  float64
+	High float64
+}
+
+// start is the price the column rose or fell from: the percent scale's
+// base when it is the first one shown.
+func (c PFColumn) start() float64 {
+	if c.Up {
+		return c.Low
+	}
+	return c.High
+}
+
+// PointFigure builds Point-and-Figure columns from closing prices using the
+// given box size and reversal count (typically 3).
+func PointFigure(ticks []Tick, box float64, reversal int) []PFColumn {
+	if len(ticks) == 0 || !validBox(box) {
+		return nil
+	}
+	if reversal < 1 {
+		reversal = 3
+	}
+	var cols []PFColumn
+	var lo, hi int // box indices of the current column
+	base := int(math.Floor(ticks[0].C / box))
+	for _, k := range ticks[1:] {
+		up := int(math.Floor(k.C / box))
+		down := int(math.Ceil(k.C / box))
+		if len(cols) == 0 {
+			switch {
+			case up > base:
+				lo, hi = base+1, up
+				cols = append(cols, PFColumn{T: k.T, Up: true})
+			case down < base:
+				lo, hi = down, base-1
+				cols = append(cols, PFColumn{T: k.T, Up: false})
+			}
+		} else if cur := &cols[len(cols)-1]; cur.Up {
+			if up > hi {
+				hi = up
+			} else if hi-down >= reversal {
+				cur.Low, cur.High = float64(lo)*box, float64(hi)*box
+				lo, hi = down, hi-1
+				cols = append(cols, PFColumn{T: k.T, Up: false})
+			}
+		} else {
+			if down < lo {
+				lo = down
+			} else if up-lo >= reversal {
+				cur.Low, cur.High = float64(lo)*box, float64(hi)*box
+				lo, hi = lo+1, up
+				cols = append(cols, PFColumn{T: k.T, Up: true})
+			}
+		}
+		if len(cols) > 0 {
+			cur := &cols[len(cols)-1]
+			cur.Low, cur.High = float64(lo)*box, float64(hi)*box
+		}
+	}
+	return cols
+}
+
+// KagiLeg is one vertical stroke of a Kagi chart, running From → To.
+// Kagi lines are thick (yang) while price is above the last shoulder and
+// thin (yin) below the last waist; a leg that crosses one of those levels
+// switches thickness at Shift.
+type KagiLeg struct {
+	T       time.Time
+	From    float64
+	To      float64
+	Yang    bool    // thickness at From
+	Shifted bool    // thickness flips at Shift
+	Shift   float64 // price at which thickness flips (when Shifted)
+}
+
+// YangAtEnd reports the leg's thickness at To.
+func (l KagiLeg) YangAtEnd() bool {
+	if l.Shifted {
+		return !l.Yang
+	}
+	return l.Yang
+}
+
+// Kagi builds Kagi legs from closing prices. The line reverses direction
+// once price retraces by at least reversal from the current extreme.
+func Kagi(ticks []Tick, reversal float64) []KagiLeg {
+	if len(ticks) < 2 || reversal <= 0 {
+		return nil
+	}
+	var legs []KagiLeg
+	cur := KagiLeg{T: ticks[0].T, From: ticks[0].C, To: ticks[0].C}
+	dir := 0
+	for _, k := range ticks[1:] {
+		switch {
+		case dir == 0:
+			if math.Abs(k.C-cur.From) >= reversal {
+				cur.To = k.C
+				dir = 1
+				if k.C < cur.From {
+					dir = -1
+				}
+			}
+		case dir > 0 && k.C > cur.To, dir < 0 && k.C < cur.To:
+			cur.To = k.C
+		case math.Abs(k.C-cur.To) >= reversal:
+			legs = append(legs, cur)
+			cur = KagiLeg{T: k.T, From: cur.To, To: k.C}
+			dir = -dir
+		}
+	}
+	if dir == 0 {
+		return nil
+	}
+	legs = append(legs, cur)
+
+	// Second pass: assign thickness now that every leg's extreme is known.
+	yang := legs[0].To > legs[0].From
+	var shoulder, waist float64
+	var haveShoulder, haveWaist bool
+	for i := range legs {
+		l := &legs[i]
+		l.Yang = yang
+		if l.To > l.From {
+			if !yang && haveShoulder && l.To > shoulder {
+				l.Shifted, l.Shift = true, shoulder
+				yang = true
+			}
+			shoulder, haveShoulder = l.To, true
+		} else {
+			if yang && haveWaist && l.To < waist {
+				l.Shifted, l.Shift = true, waist
+				yang = false
+			}
+			waist, haveWaist = l.To, true
+		}
+	}
+	return legs
+}
@@ -0,0 +1,47 @@
+package chart
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenkoKeepsNewest(t *testing.T) {
+	climb := make([]Tick, 30001)
+	for i := range climb {
+		climb[i] = bar(i, 0, 0, 0, float64(i), 0)
+	}
+	cases := []struct {
+		name      string
+		ticks     []Tick
+		box       float64
+		n         int
+		last      float64
+		truncated bool
+	}{
+		{"under the cap", climb[:101], 1, 100, 100, false},
+		{"steady climb", climb, 1, maxBricks, 30000, true},
+		{"one huge gap", []Tick{bar(0, 0, 0, 0, 100, 0), bar(1, 0, 0, 0, 1e9, 0)}, 1, maxBricks, 1e9, true},
+		{"gap down", []Tick{bar(0, 0, 0, 0, 1e6, 0), bar(1, 0, 0, 0, 0, 0)}, 1, maxBricks, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			bricks, truncated := renko(c.ticks, c.box)
+			if len(bricks) != c.n || truncated != c.truncated {
+				t.Fatalf("got %d bricks, truncated %v; want %d, %v", len(bricks), truncated, c.n, c.truncated)
+			}
+			if got := bricks[len(bricks)-1].C; got != c.last {
+				t.Errorf("last brick closes at %g, want %g", got, c.last)
+			}
+			for i := 1; i < len(bricks); i++ {
+				if bricks[i].O != bricks[i-1].C {
+					t.Fatalf("brick %d opens at %g, after one closing at %g", i, bricks[i].O, bricks[i-1].C)
+				}
+			}
+		})
+	}
+
+	out := RenderRenkoASCII(climb, 1, 100, 30, "", "caption", "")
+	if !strings.Contains(out, renkoNote) {
+		t.Errorf("caption doesn't say bricks were dropped:\n%s", out)
+	}
+}
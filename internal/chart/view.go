@@ -0,0 +1,66 @@
+package chart
+
+import "strings"
+
+// ViewMode selects how a series of ticks is drawn.
+type ViewMode int
+
+const (
+	ViewLine ViewMode = iota
+	ViewCandles
+	ViewHeikinAshi
+	ViewOHLC
+	ViewRenko
+	ViewPointFigure
+	ViewKagi
+)
+
+var viewNames = [...]string{
+	ViewLine:        "line",
+	ViewCandles:     "candles",
+	ViewHeikinAshi:  "heikin-ashi",
+	ViewOHLC:        "ohlc",
+	ViewRenko:       "renko",
+	ViewPointFigure: "pnf",
+	ViewKagi:        "kagi",
+}
+
+// String returns the name used for the view in query strings and captions.
+func (v ViewMode) String() string {
+	if v < 0 || int(v) >= len(viewNames) {
+		return "unknown"
+	}
+	return viewNames[v]
+}
+
+// Next returns the view after v, wrapping back to ViewLine.
+func (v ViewMode) Next() ViewMode {
+	return ViewMode((int(v) + 1) % len(viewNames))
+}
+
+// ViewModeNames lists the views' names in the order Next cycles them.
+func ViewModeNames() []string {
+	return append([]string(nil), viewNames[:]...)
+}
+
+// ParseViewMode maps a view name (as produced by String, plus a few
+// common aliases) back to its ViewMode.
+func ParseViewMode(s string) (ViewMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "line":
+		return ViewLine, true
+	case "candles", "candle", "kline":
+		return ViewCandles, true
+	case "heikin-ashi", "heikinashi", "ha":
+		return ViewHeikinAshi, true
+	case "ohlc", "bars":
+		return ViewOHLC, true
+	case "renko":
+		return ViewRenko, true
+	case "pnf", "p&f", "pointfigure", "point-and-figure":
+		return ViewPointFigure, true
+	case "kagi":
+		return ViewKagi, true
+	}
+	return ViewLine, false
+}
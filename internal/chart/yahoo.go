@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -111,9 +112,13 @@ func FetchIntradayOHLC(symbol, rng, interval string) ([]Tick, error) {
 		if o == 0 || h == 0 || l == 0 || c == 0 {
 			continue
 		}
+		var v int64
+		if i < len(q.Volume) {
+			v = q.Volume[i]
+		}
 		out = append(out, Tick{
 			T: time.Unix(r.Timestamp[i], 0),
-			O: o, H: h, L: l, C: c,
+			O: o, H: h, L: l, C: c, V: v,
 		})
 	}
 	if len(out) < 2 {
@@ -122,6 +127,29 @@ func FetchIntradayOHLC(symbol, rng, interval string) ([]Tick, error) {
 	return out, nil
 }
 
+// FetchCompare fetches every symbol concurrently and returns the raw
+// (unaligned) series in the order given.
+func FetchCompare(symbols []string, rng, interval string) ([]Series, error) {
+	out := make([]Series, len(symbols))
+	errs := make([]error, len(symbols))
+	var wg sync.WaitGroup
+	for i, sym := range symbols {
+		wg.Add(1)
+		go func(i int, sym string) {
+			defer wg.Done()
+			ticks, err := FetchIntradayOHLC(sym, rng, interval)
+			out[i], errs[i] = Series{Name: sym, Ticks: ticks}, err
+		}(i, sym)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", symbols[i], err)
+		}
+	}
+	return out, nil
+}
+
 func min4(a int, rest ...int) int {
 	m := a
 	for _, x := range rest {
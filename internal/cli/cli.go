@@ -3,17 +3,27 @@ package cli
 import (
 	"context"
 	"fmt"
-	"log"
+	"image"
+	"log/slog"
 	"strings"
 	"time"
 	"unicode"
 
+	"ticker-forge/internal/annot"
+	"ticker-forge/internal/cfg"
 	"ticker-forge/internal/chart"
+	"ticker-forge/internal/chart/studies"
+	"ticker-forge/internal/drawings"
 	"ticker-forge/internal/server"
+	"ticker-forge/internal/session"
+	"ticker-forge/internal/termimg"
+	"ticker-forge/internal/theme"
+	"ticker-forge/internal/watchlist"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 type Mode int
@@ -31,69 +41,244 @@ type Options struct {
 	DefaultInterval string
 	// Auto-refresh seconds in TUI (0 = off)
 	RefreshSeconds int
+	// Theme name; empty falls back to the config file, then theme.Default
+	Theme string
+	// Graphics picks the image protocol for the TUI chart: auto, sixel,
+	// kitty or none (character cells only)
+	Graphics string
+	// Annotations is the annotations file or API URL; empty uses
+	// annotations.yaml in the config directory
+	Annotations string
+	// Fresh starts the TUI from the defaults above instead of the tabs
+	// saved when it last quit
+	Fresh bool
+	// SetFlags names the flags given on the command line; of the
+	// defaults above, those set apply to the restored session's active
+	// tab
+	SetFlags map[string]bool
+	// LogLevel is debug, info (the default), warn or error. LogFile is
+	// where the log goes; empty is ticker-forge.log in the config
+	// directory in TUI mode and stderr otherwise
+	LogLevel string
+	LogFile  string
+	// Mouse is on or off; empty leaves it to config.yaml, where it is on
+	// unless turned off. M toggles it while the TUI runs
+	Mouse string
 }
 
 func Run(opts Options) error {
+	h, closeLog, err := openLog(opts.Mode, opts.LogLevel, opts.LogFile)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+	slog.SetDefault(slog.New(h))
+
+	t, err := loadTheme(opts.Theme)
+	if err != nil {
+		return err
+	}
 	if opts.Mode == ModeServe {
 		// Serve mode uses the web server; keep as-is in your project
-		return serve(opts)
+		return serve(opts, t)
+	}
+	proto, err := termimg.ParseProtocol(opts.Graphics)
+	if err != nil {
+		return err
+	}
+	if opts.Mouse != "" && opts.Mouse != "on" && opts.Mouse != "off" {
+		return fmt.Errorf("mouse %q: use on or off", opts.Mouse)
+	}
+	return runTUI(opts, t, proto)
+}
+
+// loadTheme resolves name, or the config file's theme when name is empty.
+func loadTheme(name string) (theme.Theme, error) {
+	conf, err := cfg.Load()
+	if err != nil {
+		return theme.Theme{}, err
 	}
-	return runTUI(opts)
+	return theme.Lookup(orDefault(name, conf.Theme), conf.Themes)
 }
 
-func serve(opts Options) error {
+func serve(opts Options, t theme.Theme) error {
 	return server.ListenAndServe(server.Options{
 		Port:            opts.Port,
 		DefaultSymbol:   opts.DefaultSymbol,
 		DefaultRange:    opts.DefaultRange,
 		DefaultInterval: opts.DefaultInterval,
+		Theme:           t,
+		Annotations:     opts.Annotations,
 	})
 }
 
 
 /* ---------------- TUI MODEL ---------------- */
 
-type ViewMode int
+// inputKind is what the text prompt is asking for.
+type inputKind int
+
 const (
-	ViewLine ViewMode = iota
-	ViewCandles
+	inputSymbol   inputKind = iota // the chart's ticker(s)
+	inputWatchAdd                  // symbols to add to the watchlist
+	inputWatchNew                  // the name of a new watchlist
+	inputCommand                   // a : command line
 )
 
-type model struct {
+// chartState is everything one tab charts: what it shows, how, and the
+// data fetched for it. The model embeds the active tab's (see tabs.go).
+type chartState struct {
+	id int // tells apart the tabs' fetches and refresh ticks
+
 	symbol   string
 	rng      string
 	interval string
 
-	width  int
-	height int
-
 	loading   bool
 	err       error
-	times     []time.Time
-	closes    []float64
 	lastFetch time.Time
 
+	// refresh schedule; refreshGen changes whenever it does so that the
+	// ticks of an old schedule are dropped
+	refreshEvery time.Duration
+	refreshGen   int
+
+	ticks []chart.Tick
+
+	// annotations of the current symbol, reloaded with every fetch from
+	// annotSrc (see annot.Load)
+	notes []chart.Annotation
+
+	// the current symbol's drawings (see drawing.go) and a trendline or
+	// retracement waiting for its second point
+	drawings []chart.Drawing
+	pending  *pendingDrawing
+
+	// compare mode: symbols overlaid (nil = single symbol) and their
+	// aligned, indexed series; ticks then holds the first of them
+	compare []string
+	series  []chart.Series
+
+	view    chart.ViewMode
+	scale   chart.Scale
+	profile chart.ProfileMode
+	vp      viewport
+
+	// candlestick patterns found in ticks; P shows them on the chart and
+	// in a side panel
+	found    []chart.PatternMatch
+	patterns bool
+
+	// pivot points from the prior session (L cycles the method) and
+	// support/resistance zones (Z), both drawn across the chart
+	pivots chart.PivotMethod
+	zones  bool
+
+	// volume adds a volume panel (V) to image charts and exports
+	volume bool
+}
+
+type model struct {
+	chartState
+
+	// the other tabs, in order, with a placeholder at tab (the active
+	// one's state lives in chartState); grid shows four at once
+	tabs   []chartState
+	tab    int
+	nextID int
+	grid   bool
+
+	// the key bindings, the presses of a sequence typed so far (g of gt,
+	// …) and whether the ? overlay is open
+	keys   keyMap
+	prefix string
+	help   bool
+
+	// the range/interval overlay
+	picker rangePicker
+
+	// mouse reporting (see mouse.go) and a drag panning the chart
+	mouseOn bool
+	drag    dragState
+
+	width  int
+	height int
+
 	// UI bits
 	inputMode bool
+	inputFor  inputKind
 	input     textinput.Model
 
+	// the : command lines run, oldest first, and the prompt's completion
+	// and history state
+	history []string
+	cmdLine cmdLine
+
+	// panes (see layout.go): their arrangement, the focused one, the
+	// watchlist's state, the studies listed and the log
+	layout  cfg.Layout
+	focus   string
+	wl      watchPane
+	studies []studies.Spec
+	log     *logBuffer
+
 	// refresh
-	refreshEvery time.Duration
-	ticker       *time.Ticker
-	cancel       context.CancelFunc
+	ticker *time.Ticker
+	cancel context.CancelFunc
 
-	ticks []chart.Tick
+	// where annotations are loaded from (see annot.Load)
+	annotSrc string
+
+	palette theme.Palette
+	theme   theme.Theme
+
+	// image output: the terminal's protocol (termimg.None when it has
+	// none), whether it is in use (gi toggles) and the cell size in pixels
+	graphics     termimg.Protocol
+	images       bool
+	cellW, cellH int
+	imgCache     *imageCache
+
+	// renderer keeps the text chart's buffers between frames
+	renderer *chart.Renderer
 
-	view ViewMode
+	// status is a one-off note for the caption, e.g. where e saved a file
+	status string
 }
 
 var (
-	titleStyle = lipgloss.NewStyle().Bold(true)
-	subtle     = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
-	errStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
-	hintStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Italic(true)
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	subtle      = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Bold(true)
+	hintStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Italic(true)
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 )
 
+// applyTheme restyles the TUI for t and tells lipgloss which color depth
+// to render for (Ascii under NO_COLOR).
+func applyTheme(t theme.Theme, p termenv.Profile) {
+	lipgloss.SetColorProfile(p)
+	titleStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(t.Accent))
+	subtle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Subtle))
+	errStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Error)).Bold(true)
+	hintStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Subtle)).Italic(true)
+	accentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Accent))
+}
+
+const symbolPlaceholder = "Ticker Symbol (e.g. AAPL, or AAPL,MSFT,QQQ to compare)"
+
+// validSymbols allows letters, digits, dot, hyphen, and comma/space
+// between symbols to compare; empty is allowed while typing.
+func validSymbols(s string) error {
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' || r == ',' || r == ' ' {
+			continue
+		}
+		return fmt.Errorf("invalid char: %q", r)
+	}
+	return nil
+}
+
 func initialModel(opts Options) model {
 	if opts.DefaultSymbol == "" {
 		opts.DefaultSymbol = "AAPL"
@@ -106,98 +291,165 @@ func initialModel(opts Options) model {
 	}
 	ti := textinput.New()
 	ti.Prompt = "> "
-	ti.Placeholder = "Ticker Symbol (e.g. AAPL)"
-	ti.CharLimit = 16
-	ti.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	ti.Placeholder = symbolPlaceholder
+	ti.CharLimit = 64
+	ti.Cursor.Style = accentStyle
 	ti.TextStyle = lipgloss.NewStyle().Bold(true)
-	ti.Validate = func(s string) error {
-		// allow letters, digits, dot, hyphen; empty is allowed while typing
-		for _, r := range s {
-			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '-' {
-				continue
-			}
-			return fmt.Errorf("invalid char: %q", r)
-		}
-		return nil
-	}
+	ti.Validate = validSymbols
 
 	var refresh time.Duration
 	if opts.RefreshSeconds > 0 {
 		refresh = time.Duration(opts.RefreshSeconds) * time.Second
 	}
 
-	return model{
+	store, err := watchlist.Load("")
+	var status string
+	if err != nil {
+		status = "watchlists: " + err.Error()
+	}
+	store.Active(opts.DefaultSymbol)
+	keys, _, _ := newKeyMap("", nil)
+
+	first := chartState{
 		symbol:       strings.ToUpper(opts.DefaultSymbol),
 		rng:          opts.DefaultRange,
 		interval:     opts.DefaultInterval,
-		input:        ti,
 		refreshEvery: refresh,
 		loading:      true,
+		vp:           newViewport(),
+	}
+	return model{
+		chartState: first,
+		tabs:       []chartState{first},
+		keys:       keys,
+		mouseOn:    true,
+		layout:     defaultLayout(),
+		focus:      paneChart,
+		wl:         watchPane{store: store, quotes: map[string]chart.Quote{}},
+		studies:    defaultStudies,
+		log:        &logBuffer{},
+		status:     status,
+		input:      ti,
+		annotSrc:   opts.Annotations,
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return fetchCmd(m.symbol, m.rng, m.interval)
+	return tea.Batch(m.fetchAll(), quotesCmd(m.wl.store.Active().Symbols, true))
 }
 
 type fetchedMsg struct {
-	times  []time.Time
-	closes []float64
+	// the tab fetched for, its refresh generation then and whether the
+	// fetch belongs to its refresh schedule (which goes on afterwards)
+	tab       int
+	gen       int
+	scheduled bool
+	took      time.Duration
+
+	ticks  []chart.Tick
+	series []chart.Series // compare mode only
 	err    error
+
+	notes    []chart.Annotation
+	notesErr error // reported in the status line; the chart still draws
+
+	drawings    []chart.Drawing
+	drawingsErr error // likewise
+}
+
+// fetch loads the current symbol, or every compared symbol in compare mode.
+func (m model) fetch() tea.Cmd {
+	return m.fetchTab(false)
 }
 
-func fetchCmd(symbol, rng, interval string) tea.Cmd {
+// fetchTab is fetch for the active tab's refresh schedule when scheduled
+// is set.
+func (m model) fetchTab(scheduled bool) tea.Cmd {
+	cmd := fetchCmd(m.symbol, m.rng, m.interval, m.annotSrc)
+	if len(m.compare) > 1 {
+		cmd = compareCmd(m.compare, m.rng, m.interval)
+	}
+	id, gen, symbols, rng, interval := m.id, m.refreshGen, m.symbols(), m.rng, m.interval
 	return func() tea.Msg {
-		t, c, err := chart.FetchIntraday(symbol, rng, interval)
-		return fetchedMsg{times: t, closes: c, err: err}
+		slog.Debug("fetching", "symbols", symbols, "range", rng, "interval", interval)
+		start := time.Now()
+		msg := cmd().(fetchedMsg)
+		msg.tab, msg.gen, msg.scheduled = id, gen, scheduled
+		msg.took = time.Since(start).Round(time.Millisecond)
+		return msg
 	}
 }
 
-type tickMsg struct{}
-
-func tickCmd(d time.Duration) tea.Cmd {
-	if d <= 0 {
-		return nil
+func fetchCmd(symbol, rng, interval, annotSrc string) tea.Cmd {
+	return func() tea.Msg {
+		ticks, err := chart.FetchIntradayOHLC(symbol, rng, interval)
+		notes, notesErr := annot.Load(annotSrc)
+		store, drawingsErr := drawings.Load("")
+		return fetchedMsg{ticks: ticks, err: err,
+			notes: chart.AnnotationsFor(notes, symbol), notesErr: notesErr,
+			drawings: store.For(symbol), drawingsErr: drawingsErr}
 	}
-	return tea.Tick(d, func(time.Time) tea.Msg { return tickMsg{} })
 }
 
-// makeTicksFromCloses builds OHLC from consecutive closes (prev→open; hi/lo = max/min)
-func makeTicksFromCloses(ts []time.Time, closes []float64) []chart.Tick {
-	if len(closes) < 2 {
-		return nil
+func compareCmd(symbols []string, rng, interval string) tea.Cmd {
+	return func() tea.Msg {
+		raw, err := chart.FetchCompare(symbols, rng, interval)
+		if err != nil {
+			return fetchedMsg{err: err}
+		}
+		series := chart.CompareSeries(raw)
+		return fetchedMsg{ticks: series[0].Ticks, series: series}
 	}
-	n := len(closes) - 1
-	out := make([]chart.Tick, 0, n)
-	for i := 1; i < len(closes); i++ {
-		o := closes[i-1]
-		c := closes[i]
-		h, l := o, o
-		if c > h { h = c }
-		if c < l { l = c }
-		var t time.Time
-		if i < len(ts) { t = ts[i] }
-		out = append(out, chart.Tick{T: t, O: o, H: h, L: l, C: c})
+}
+
+// symbols is what the ticker prompt shows for the current selection.
+func (s chartState) symbols() string {
+	if len(s.compare) > 1 {
+		return strings.Join(s.compare, ",")
 	}
-	return out
+	return s.symbol
 }
 
+// tickMsg asks tab to refresh, if its schedule is still generation gen.
+type tickMsg struct{ tab, gen int }
+
+func tickCmd(tab, gen int, d time.Duration) tea.Cmd {
+	if d <= 0 {
+		return nil
+	}
+	return tea.Tick(d, func(time.Time) tea.Msg { return tickMsg{tab, gen} })
+}
 
+// Update handles msg and logs any new status line at debug level.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	next, cmd := m.update(msg)
+	if n, ok := next.(model); ok && n.status != m.status && n.status != "" {
+		slog.Debug(n.status)
+	}
+	return next, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if m.inputMode {
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if m.inputFor == inputCommand {
+				var ok bool
+				if m, ok = m.commandKey(msg.String()); ok {
+					return m, nil
+				}
+			}
 			switch msg.String() {
 			case "enter":
-				val := strings.ToUpper(strings.TrimSpace(m.input.Value()))
 				m.input.Blur()
 				m.inputMode = false
-				if val != "" && val != m.symbol {
-					m.symbol = val
-					m.loading = true
-					return m, fetchCmd(m.symbol, m.rng, m.interval)
+				switch m.inputFor {
+				case inputSymbol:
+					return m.load(chart.ParseSymbols(m.input.Value()))
+				case inputCommand:
+					return m.runCommand(m.input.Value())
 				}
-				return m, nil
+				return m.watchInput(m.inputFor, m.input.Value())
 			case "esc":
 				m.input.Blur()
 				m.inputMode = false
@@ -206,101 +458,422 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		var cmd tea.Cmd
 		m.input, cmd = m.input.Update(msg)
-		m.input.SetValue(strings.ToUpper(m.input.Value()))
+		if m.inputFor == inputSymbol || m.inputFor == inputWatchAdd {
+			m.input.SetValue(strings.ToUpper(m.input.Value()))
+		}
 		return m, cmd
 	}
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		if m.graphics != termimg.None {
+			m.cellW, m.cellH = termimg.CellSize()
+		}
 		return m, nil
 
 	case fetchedMsg:
-		m.loading = false
-		m.err = msg.err
-		if msg.err == nil {
-			m.times = msg.times
-			m.closes = msg.closes
-			m.lastFetch = time.Now()
-			m.ticks = makeTicksFromCloses(m.times, m.closes)
-			if len(m.closes) < 2 {
-				// keep a helpful status instead of trying to render
-				m.err = fmt.Errorf("no datapoints returned (try another interval/range)")
-			}	
-		}
-		// keep ticking if enabled
-		return m, tickCmd(m.refreshEvery)
+		return m.onTab(msg.tab, func(m model) (model, tea.Cmd) { return m.fetched(msg) })
+
+	case exportedMsg:
+		m.status = "saved " + msg.path
+		if msg.err != nil {
+			m.status = "export failed: " + msg.err.Error()
+		}
+		return m, nil
+
+	case quotesMsg:
+		for sym, q := range msg.quotes {
+			m.wl.quotes[sym] = q
+		}
+		m.wl.err = msg.err
+		if msg.periodic {
+			return m, quotesTick()
+		}
+		return m, nil
+
+	case quotesTickMsg:
+		if !m.shows(paneWatchlist) {
+			return m, quotesTick()
+		}
+		return m, quotesCmd(m.wl.store.Active().Symbols, true)
+
+	case layoutSavedMsg:
+		if msg.err != nil {
+			m.status = "saving the layout failed: " + msg.err.Error()
+		}
+		return m, nil
+
+	case watchlistSavedMsg:
+		if msg.err != nil {
+			m.status = "saving watchlists failed: " + msg.err.Error()
+		}
+		return m, nil
+
+	case drawingsSavedMsg:
+		if msg.err != nil {
+			m.status = "saving drawings failed: " + msg.err.Error()
+		}
+		return m, nil
 
 	case tickMsg:
-		// periodic refresh
-		m.loading = true
-		return m, fetchCmd(m.symbol, m.rng, m.interval)
+		// periodic refresh, unless the tab's schedule changed since
+		return m.onTab(msg.tab, func(m model) (model, tea.Cmd) {
+			if msg.gen != m.refreshGen {
+				return m, nil
+			}
+			m.loading = true
+			return m, m.fetchTab(true)
+		})
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Sequence(tea.ExitAltScreen, tea.Quit)
+		return m.key(msg)
 
-		case "r": // refresh now
-			m.loading = true
-			return m, fetchCmd(m.symbol, m.rng, m.interval)
-
-		case "/": // edit ticker
-			m.inputMode = true
-			m.input.SetValue(m.symbol)
-			m.input.CursorEnd()
-			m.input.Focus()
-			return m, nil
-			
-		case "1":
-			m.interval = "1m"
-			m.loading = true
-			return m, fetchCmd(m.symbol, m.rng, m.interval)
-		case "2":
-			m.interval = "5m"
-			m.loading = true
-			return m, fetchCmd(m.symbol, m.rng, m.interval)
-		case "3":
-			m.interval = "15m"
-			m.loading = true
-			return m, fetchCmd(m.symbol, m.rng, m.interval)
-		case "d":
-			m.rng = "1d"
-			m.loading = true
-			return m, fetchCmd(m.symbol, m.rng, m.interval)
-		case "w":
-			m.rng = "5d"
-			m.loading = true
-			return m, fetchCmd(m.symbol, m.rng, m.interval)
-		case "c":
-			if m.view == ViewLine {
-				m.view = ViewCandles
-			} else {
-				m.view = ViewLine
+	case tea.MouseMsg:
+		return m.mouse(msg)
+	}
+	return m, nil
+}
+
+// chartKey handles the actions on the active tab's chart and the general
+// ones. It reports false for any other action.
+func (m model) chartKey(a action) (model, tea.Cmd, bool) {
+	if n, ok := tabNumber(a); ok {
+		if n > len(m.tabs) {
+			m.status = fmt.Sprintf("no tab %d (%s opens one)", n, m.keys.keys(actTabNew))
+			return m, nil, true
+		}
+		return m.switchTab(n - 1), nil, true
+	}
+	switch a {
+	case actQuit:
+		return m, tea.Sequence(tea.ExitAltScreen, tea.Quit), true
+
+	case actHelp:
+		m.help = true
+	case actCommand:
+		m.cmdLine = cmdLine{hist: len(m.history)}
+		return m.prompt(inputCommand, ""), nil, true
+	case actRefresh: // refresh now
+		m.loading = true
+		return m, m.fetch(), true
+	case actTicker: // edit ticker
+		return m.prompt(inputSymbol, m.symbols()), nil, true
+	case actWatchAdd: // add symbols to the watchlist
+		return m.prompt(inputWatchAdd, m.symbols()), nil, true
+	case actWatchNewList:
+		return m.prompt(inputWatchNew, ""), nil, true
+	case actExport: // export the current view as PNG
+		if len(m.ticks) == 0 {
+			m.status = "export: nothing charted yet"
+			return m, nil, true
+		}
+		m.status = "exporting…"
+		return m, m.exportCmd("png"), true
+	case actImages: // image or character-cell chart
+		m.images = !m.images && m.graphics != termimg.None
+	case actMouse:
+		m.mouseOn = !m.mouseOn
+		m.drag.active = false
+		if !m.mouseOn {
+			m.status = "mouse off"
+			return m, tea.DisableMouse, true
+		}
+		m.status = "mouse on"
+		return m, tea.EnableMouseAllMotion, true
+
+	// tabs
+	case actTabNext:
+		return m.switchTab((m.tab + 1) % len(m.tabs)), nil, true
+	case actTabPrev:
+		return m.switchTab((m.tab - 1 + len(m.tabs)) % len(m.tabs)), nil, true
+	case actTabNew:
+		m, cmd := m.newTab()
+		return m, cmd, true
+	case actTabClose:
+		return m.closeTab(), nil, true
+	case actGrid: // 2×2 grid of tabs on/off
+		m.grid = !m.grid
+		m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	case actAutoRefresh: // cycle this tab's refresh: off → 15s → 30s → 1m → 5m
+		m, cmd := m.stepRefresh()
+		return m, cmd, true
+
+	// what is charted and how
+	case actPicker:
+		return m.openPicker(), nil, true
+	case actIntervalNext, actIntervalPrev:
+		delta := 1
+		if a == actIntervalPrev {
+			delta = -1
+		}
+		m, cmd := m.stepInterval(delta)
+		return m, cmd, true
+	case actRange1d, actRange5d:
+		rng := "1d"
+		if a == actRange5d {
+			rng = "5d"
+		}
+		m.rng, m.interval, m.status = fitPair(rng, m.interval, "range")
+		m.vp = newViewport()
+		m.loading = true
+		return m, m.fetch(), true
+	case actView: // cycle line → candles → heikin-ashi → ohlc → renko → pnf → kagi
+		m.view = m.view.Next()
+		m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	case actScale: // cycle linear → log → percent
+		m.scale = m.scale.Next()
+	case actProfile: // cycle no profile → volume → TPO
+		m.profile = m.profile.Next()
+		m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	case actPatterns: // candlestick patterns on/off
+		m.patterns = !m.patterns
+		m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	case actPivots: // cycle no pivots → classic → fib → camarilla
+		m.pivots = m.pivots.Next()
+		if _, _, _, ok := chart.PriorSession(m.ticks); m.pivots != chart.PivotNone && !ok {
+			m.status = "pivots need the prior session (try a 5d range)"
+		}
+	case actZones: // support/resistance zones on/off
+		m.zones = !m.zones
+	case actVolume: // volume panel on/off
+		m.volume = !m.volume
+		if !m.images {
+			m.status = "volume panel off"
+			if m.volume {
+				m.status = "volume panel on: shown in image charts and exports"
 			}
-			return m, nil
 		}
+
+	// viewport: crosshair, zoom, pan
+	case actCursorLeft, actCursorRight:
+		delta := -1
+		if a == actCursorRight {
+			delta = 1
+		}
+		m.vp.move(delta, len(m.ticks), m.chart().Capacity())
+	case actZoomIn, actZoomOut:
+		m.vp.zoom(a == actZoomIn, len(m.ticks), m.chart().Capacity())
+	case actPanLeft:
+		m.vp.pan(m.panStep(), len(m.ticks), m.chart().Capacity())
+	case actPanRight:
+		m.vp.pan(-m.panStep(), len(m.ticks), m.chart().Capacity())
+	case actCancel: // a drawing in progress, else the view
+		if m.pending != nil {
+			m.pending, m.status = nil, ""
+			break
+		}
+		m.vp = newViewport()
+	case actReset:
+		m.vp = newViewport()
+
+	// drawings at the crosshair: level, trendline, retracement, undo
+	case actLevel, actTrend, actFib, actUndo:
+		m, cmd := m.draw(a)
+		return m, cmd, true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// prompt opens the text prompt for kind, filled in with value.
+func (m model) prompt(kind inputKind, value string) model {
+	m.inputMode, m.inputFor = true, kind
+	m.input.Prompt, m.input.Validate, m.input.Placeholder = "> ", validSymbols, symbolPlaceholder
+	if kind == inputCommand {
+		m.input.Prompt, m.input.Validate, m.input.Placeholder = ":", nil, "symbol MSFT, range 5y, study add ema 50…"
+	}
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	m.input.Focus()
+	return m
+}
+
+// load charts syms: one symbol, or several to compare. It does nothing
+// when they are already shown.
+func (m model) load(syms []string) (model, tea.Cmd) {
+	if len(syms) == 0 || strings.Join(syms, ",") == m.symbols() {
 		return m, nil
 	}
-	return m, nil
+	m.symbol = syms[0]
+	m.pending, m.drawings = nil, nil
+	m.compare = nil
+	if len(syms) > 1 {
+		m.compare = syms
+	}
+	m.vp = newViewport()
+	m.loading = true
+	return m, m.fetch()
+}
+
+// fetched applies msg to the active tab and, when the fetch belongs to
+// its refresh schedule, schedules the next one.
+func (m model) fetched(msg fetchedMsg) (model, tea.Cmd) {
+	m.loading = false
+	m.err = msg.err
+	l := slog.With("symbols", m.symbols(), "range", m.rng, "interval", m.interval, "took", msg.took)
+	if msg.err != nil {
+		l.Error("fetch failed", "err", msg.err)
+	}
+	if msg.err == nil {
+		l.Info("fetched", "bars", len(msg.ticks))
+		m.lastFetch = time.Now()
+		m.ticks = msg.ticks
+		m.found = chart.FindPatterns(m.ticks)
+		m.series = msg.series
+		m.notes = msg.notes
+		if msg.notesErr != nil {
+			m.status = "annotations: " + msg.notesErr.Error()
+		}
+		m.drawings = msg.drawings
+		if msg.drawingsErr != nil {
+			m.status = "drawings: " + msg.drawingsErr.Error()
+		}
+		m.vp.clamp(len(m.ticks), m.chart().Capacity())
+		if len(m.ticks) < 2 {
+			// keep a helpful status instead of trying to render
+			m.err = fmt.Errorf("no datapoints returned (try another interval/range)")
+			l.Warn("no datapoints")
+		}
+	}
+	// keep ticking if enabled
+	if !msg.scheduled || msg.gen != m.refreshGen {
+		return m, nil
+	}
+	return m, tickCmd(m.id, m.refreshGen, m.refreshEvery)
+}
+
+// chart returns the ASCII chart settings for the current view and window
+// size; header/caption/footer and cursor are filled in by View.
+func (m model) chart() chart.ASCIIChart {
+	w, h := m.width, m.height
+	if w <= 0 {
+		w = 100
+	}
+	if h <= 0 {
+		h = 30
+	}
+	if m.usePanes() {
+		w, h, _ = m.paneSize(paneChart)
+	}
+	if m.grid {
+		w, h = gridSize(w, h)
+	}
+	return m.chartSized(w, h)
+}
+
+// chartSized is chart for a w×h area.
+func (m model) chartSized(w, h int) chart.ASCIIChart {
+	view := m.view
+	if len(m.series) > 1 {
+		view = chart.ViewLine // comparisons are always drawn as lines
+	}
+	ch := chart.ASCIIChart{View: view, Scale: m.scale, Profile: m.profile, Width: w, Height: h, Cursor: -1, Palette: &m.palette}
+	if m.showPatternPanel() {
+		ch.Width = max(w-patternPanelWidth, 40)
+		ch.Patterns = m.found
+	}
+	if len(m.series) <= 1 {
+		ch.Levels = chart.SessionPivots(m.pivots, m.ticks)
+		if m.zones {
+			ch.Zones = chart.SRZones(m.ticks, chart.MaxZones)
+		}
+	}
+	return ch
+}
+
+// showPatternPanel reports whether View puts the pattern panel beside the
+// chart: patterns are on and a single symbol is drawn as text.
+func (m model) showPatternPanel() bool {
+	return m.patterns && !m.images && !m.grid && len(m.series) <= 1
+}
+
+// profileCaption sums up the profile of the visible bars: its point of
+// control and value area.
+func profileCaption(mode chart.ProfileMode, ticks []chart.Tick) string {
+	p := chart.VolumeProfile(ticks, 50)
+	if mode == chart.ProfileTPO {
+		p = chart.TPOProfile(ticks, 50)
+	}
+	if p.Counts == nil {
+		return mode.String() + " profile: no data"
+	}
+	return fmt.Sprintf("%s POC %.2f  VA %.2f–%.2f", mode, p.Price(p.POC),
+		p.Lo+float64(p.VAL)*p.Step, p.Lo+float64(p.VAH+1)*p.Step)
+}
+
+// panStep is how far h/l scroll: a fifth of the visible bars.
+func (m model) panStep() int {
+	return max(1, m.vp.visible(len(m.ticks), m.chart().Capacity())/5)
 }
 
 func (m model) View() string {
-	// header
-	header := titleStyle.Render("Ticker Forge") + "\n" +
-		fmt.Sprintf("%s  %s  %s  %s  %s  %s\n",
-		  subtle.Render("(/) change ticker"),
-		  subtle.Render("[1]=1m"),
-		  subtle.Render("[2]=5m"),
-		  subtle.Render("[3]=15m"),
-		  subtle.Render("[d]=1d, [w]=5d"),
-		  subtle.Render("[c]=candles/line"),
-		)
+	if m.help || m.picker.open {
+		w, h := m.width, m.height
+		if w <= 0 || h <= 0 {
+			w, h = 100, 30
+		}
+		if m.picker.open {
+			return m.pickerView(w, h)
+		}
+		return m.keys.helpView(w, h)
+	}
+	if !m.usePanes() {
+		if m.grid {
+			w, h := m.width, m.height
+			if w <= 0 || h <= 0 {
+				w, h = 100, 30
+			}
+			return m.viewGrid(w, h)
+		}
+		return m.viewChart()
+	}
+	return m.renderLayout(m.layout, m.width, m.height)
+}
+
+// headerHints are the actions listed under the title.
+var headerHints = []action{actTicker, actIntervalNext, actIntervalPrev, actRange1d, actRange5d, actPicker, actView, actScale,
+	actCursorLeft, actCursorRight, actZoomIn, actZoomOut, actPanLeft, actPanRight, actReset}
+
+// footerHints are the actions listed under the chart, those of the panes
+// shown included.
+func (m model) footerHints() []action {
+	hints := []action{actRefresh, actCommand, actProfile, actPatterns, actPivots, actZones, actLevel, actTrend, actFib, actUndo,
+		actExport, actTabNew, actTabNext, actGrid, actAutoRefresh}
+	if m.usePanes() {
+		hints = append(hints, actFocusNext, actSplitRight, actHidePane, actToggleWatch)
+	}
+	if m.showWatchlist() {
+		hints = append(hints, actWatchOpen, actWatchAdd, actWatchRemove)
+	}
+	if m.graphics != termimg.None {
+		hints = append(hints, actImages)
+	}
+	return append(hints, actHelp, actQuit)
+}
+
+// viewChart draws the chart pane: header, chart, caption and key hints.
+func (m model) viewChart() string {
+	header := m.chartHeader()
 	// input mode
 	if m.inputMode {
+		label := "Symbol: "
+		switch m.inputFor {
+		case inputWatchAdd:
+			label = "Add to " + m.wl.store.Active().Name + ": "
+		case inputWatchNew:
+			label = "New watchlist: "
+		case inputCommand:
+			label = ""
+		}
+		hint := hintStyle.Render("Press Enter to apply, Esc to cancel")
+		if m.inputFor == inputCommand {
+			hint = subtle.Render(m.completionHint()) + "\n" +
+				hintStyle.Render("Press Enter to run, Esc to cancel, Tab to complete, ↑/↓ for history")
+		}
 		return header + "\n" +
-			"Symbol: " + m.input.View() + "\n\n" +
-			hintStyle.Render("Press Enter to apply, Esc to cancel")
+			label + m.input.View() + "\n\n" + hint
 	}
 
 	// error / loading
@@ -310,46 +883,194 @@ func (m model) View() string {
 	if m.loading {
 		return header + "\n" + hintStyle.Render("loading…") + "\n"
 	}
-	if len(m.closes) < 2 {
-		return header + "\n" + hintStyle.Render("no data yet (try 'r' to refresh or change ticker with '/')") + "\n"
+	if len(m.ticks) < 2 {
+		return header + "\n" + hintStyle.Render(fmt.Sprintf("no data yet (try %s to refresh or change ticker with %s)", m.keys.keys(actRefresh), m.keys.keys(actTicker))) + "\n"
 	}
-	
 
-	// shared bits for either view
-	w := m.width
-	h := m.height
-	if w <= 0 {
-		w = 100
+	ch, start, end := m.framedChart()
+	if len(m.series) > 1 {
+		return m.viewCompare(ch, start, end)
 	}
-	if h <= 0 {
-		h = 30
+	visible := m.ticks[start:end]
+	ch.Annotations, ch.Drawings = m.notes, m.drawings
+	if m.images {
+		return m.viewImage(ch, func(ic chart.ImageChart) image.Image { return ic.Draw(visible) })
+	}
+	if m.showPatternPanel() {
+		return m.withPatternPanel(ch, visible, start)
+	}
+	if m.renderer != nil {
+		return m.renderer.Render(ch, visible)
+	}
+	return ch.Render(visible)
+}
+
+// chartHeader is the title, tab bar and key hints above the chart; in
+// text mode it also removes any image left over from g.
+func (m model) chartHeader() string {
+	wipe := ""
+	if !m.images {
+		wipe = termimg.Clear(m.graphics)
 	}
-	last := m.closes[len(m.closes)-1]
-	caption := fmt.Sprintf("%s  %s/%s   last: %.2f   fetched: %s",
-		m.symbol, m.rng, m.interval, last, m.lastFetch.Format("15:04:05"))
-	footer := "\n" + hintStyle.Render("r=refresh • /=ticker • c=candles/line • q=quit")
+	return titleStyle.Render("Ticker Forge") + m.tabBar() + wipe + "\n" +
+		m.keys.shortHelp(headerHints...) + "\n"
+}
 
-	if m.view == ViewCandles {
-		if len((m.ticks)) < 2 {
-			return header + "\n" + hintStyle.Render("no ticks to render yet") + "\n"
+// framedChart is chart with the header, caption and footer viewChart
+// puts around it, and the bars [start, end) it shows. It expects at
+// least two ticks.
+func (m model) framedChart() (ch chart.ASCIIChart, start, end int) {
+	ch = m.chart()
+	start, end = m.vp.window(len(m.ticks), ch.Capacity())
+	footer := "\n" + m.keys.shortHelp(m.footerHints()...)
+	if m.status != "" {
+		footer += "   " + subtle.Render(m.status)
+	}
+	ch.Header, ch.Footer = m.chartHeader(), footer
+	if len(m.series) > 1 {
+		ch.Caption = m.compareCaption(start, end)
+	} else {
+		ch.Caption = m.caption(ch, start, end)
+	}
+	if c := m.vp.cursor; c >= 0 && c < len(m.ticks) {
+		ch.Cursor = c - start
+	}
+	return ch, start, end
+}
+
+// caption sums up the chart of bars [start, end) above it; the second
+// line describes the crosshair bar.
+func (m model) caption(ch chart.ASCIIChart, start, end int) string {
+	visible := m.ticks[start:end]
+	last := m.ticks[len(m.ticks)-1].C
+	caption := fmt.Sprintf("%s  %s/%s  %s  %s   last: %.2f   fetched: %s",
+		m.symbol, m.rng, m.interval, m.view, m.scale, last, m.lastFetch.Format("15:04:05"))
+	if end-start < len(m.ticks) {
+		caption += fmt.Sprintf("   bars %d–%d of %d", start+1, end, len(m.ticks))
+	}
+	if m.profile != chart.ProfileNone {
+		caption += "   " + profileCaption(m.profile, visible)
+	}
+	if m.pivots != chart.PivotNone {
+		caption += "   " + m.pivots.String() + " pivots"
+		if ch.Levels == nil {
+			caption += ": need the prior session"
 		}
-		return chart.RenderCandlesASCII(m.ticks, w, h, header, caption, footer)
 	}
-	return chart.RenderLineASCII(m.closes, w, h, header, caption, footer)
+	if m.zones {
+		caption += fmt.Sprintf("   %d S/R zones", len(ch.Zones))
+	}
+	if c := m.vp.cursor; c >= 0 && c < len(m.ticks) {
+		k := m.ticks[c]
+		caption += "\n" + fmt.Sprintf("▸ %s  O %.2f  H %.2f  L %.2f  C %.2f  V %d",
+			k.T.Format("2006-01-02 15:04"), k.O, k.H, k.L, k.C, k.V)
+		for _, a := range chart.AnnotationsAt(m.notes, m.ticks, c) {
+			caption += "  • " + a.String()
+		}
+		if m.patterns {
+			for _, p := range chart.PatternsAt(m.found, m.ticks, c) {
+				caption += "  • " + string(p.Pattern)
+			}
+		}
+	}
+	return caption
 }
 
+// compareCaption is caption for compared symbols; the crosshair line
+// lists each symbol's change since start.
+func (m model) compareCaption(start, end int) string {
+	caption := fmt.Sprintf("%s  %s/%s  compare  %s   fetched: %s",
+		strings.Join(m.compare, " vs "), m.rng, m.interval, m.scale, m.lastFetch.Format("15:04:05"))
+	if end-start < len(m.ticks) {
+		caption += fmt.Sprintf("   bars %d–%d of %d", start+1, end, len(m.ticks))
+	}
+	if c := m.vp.cursor; c >= 0 && c < len(m.ticks) {
+		caption += "\n▸ " + m.ticks[c].T.Format("2006-01-02 15:04")
+		for _, s := range m.series {
+			if base := s.Ticks[start].C; base != 0 {
+				caption += fmt.Sprintf("  %s %+.2f%%", s.Name, (s.Ticks[c].C/base-1)*100)
+			}
+		}
+	}
+	return caption
+}
 
-func runTUI(opts Options) error {
-	model := initialModel(opts)
-	log.Printf("Model: %+v\n", model)
+// viewCompare draws the compared symbols over bars [start, end).
+func (m model) viewCompare(ch chart.ASCIIChart, start, end int) string {
+	visible := make([]chart.Series, len(m.series))
+	for i, s := range m.series {
+		visible[i] = chart.Series{Name: s.Name, Ticks: s.Ticks[start:end]}
+	}
+	if m.images {
+		return m.viewImage(ch, func(ic chart.ImageChart) image.Image { return ic.DrawCompare(visible) })
+	}
+	if m.renderer != nil {
+		return m.renderer.RenderCompare(ch, visible)
+	}
+	return ch.RenderCompare(visible)
+}
 
-	altScreen := tea.WithAltScreen()
-	log.Printf("AltScreen Option: %+v\n", altScreen)
 
-	p := tea.NewProgram(model, altScreen)
-	log.Printf("Program: %+v\n", p)
-	_, err := p.Run()
-	return err
+func runTUI(opts Options, t theme.Theme, proto termimg.Protocol) error {
+	profile := theme.Detect()
+	applyTheme(t, profile)
+	model := initialModel(opts)
+	slog.SetDefault(slog.New(paneHandler{buf: model.log, next: slog.Default().Handler()}))
+	model.palette = t.Palette(profile)
+	model.theme = t
+	model.graphics = proto
+	model.images = proto != termimg.None
+	model.cellW, model.cellH = termimg.CellSize()
+	model.imgCache = &imageCache{}
+	model.renderer = &chart.Renderer{}
+	if conf, err := cfg.Load(); err == nil {
+		model.layout = cleanLayout(conf.Layout)
+		keys, warnings, err := newKeyMap(conf.Keymap, conf.Keys)
+		if err != nil {
+			return err
+		}
+		model.keys = keys
+		if conf.Mouse != nil {
+			model.mouseOn = *conf.Mouse
+		}
+		for _, w := range warnings {
+			slog.Warn("keys: " + w)
+		}
+		if len(warnings) > 0 {
+			model.status = fmt.Sprintf("keys: %s (see the log pane)", warnings[0])
+		}
+	}
+	if opts.Mouse != "" {
+		model.mouseOn = opts.Mouse == "on"
+	}
+	if !opts.Fresh {
+		if s, err := session.Load(""); err != nil {
+			model.status = "session: " + err.Error()
+		} else if s != nil {
+			model = model.restore(s).withFlags(opts)
+		}
+	}
+	popts := []tea.ProgramOption{tea.WithAltScreen()}
+	if model.mouseOn {
+		// every motion, not only drags, so hovering moves the crosshair
+		popts = append(popts, tea.WithMouseAllMotion())
+	}
+	p := tea.NewProgram(model, popts...)
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+	if err := saveSession(final); err != nil {
+		return fmt.Errorf("saving the session: %w", err)
+	}
+	return nil
+}
+
+func orDefault(val, fallback string) string {
+	if val != "" {
+		return val
+	}
+	return fallback
 }
 
 func max(a, b int) int {
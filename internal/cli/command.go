@@ -0,0 +1,382 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/chart/studies"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ranges and cmdIntervals are what :range and :interval accept: the
+// values Yahoo's chart API takes.
+var (
+	ranges       = []string{"1d", "5d", "1mo", "3mo", "6mo", "1y", "2y", "5y", "10y", "ytd", "max"}
+	cmdIntervals = []string{"1m", "2m", "5m", "15m", "30m", "60m", "90m", "1h", "1d", "5d", "1wk", "1mo", "3mo"}
+)
+
+// historyLimit is how many command lines : remembers.
+const historyLimit = 100
+
+// command is one : command. Every action is a command too, run by its
+// name (:grid, :tab-new); commands are tried first.
+type command struct {
+	name  string
+	usage string
+	help  string
+	// args lists the candidates for argument n (0-based) given the ones
+	// before it, for tab completion; nil when there are none
+	args func(m model, n int, before []string) []string
+	run  func(m model, args []string) (model, tea.Cmd, error)
+}
+
+var commands = []command{
+	{name: "symbol", usage: "SYMBOL[,SYMBOL…]", help: "chart a symbol, or several to compare",
+		args: watchedSymbols, run: cmdSymbol},
+	{name: "compare", usage: "SYMBOL… | off", help: "add symbols to compare, or go back to one",
+		args: watchedSymbols, run: cmdCompare},
+	{name: "range", usage: "[RANGE]", help: "e.g. 5d, 1mo, 5y, max; none opens the picker",
+		args: fixedArgs(ranges), run: cmdRange},
+	{name: "interval", usage: "[INTERVAL]", help: "e.g. 5m, 1h, 1d, 1wk; none opens the picker",
+		args: fixedArgs(cmdIntervals), run: cmdInterval},
+	{name: "view", usage: "[MODE]", help: "set or cycle the chart view",
+		args: fixedArgs(chart.ViewModeNames()), run: cmdView},
+	{name: "scale", usage: "[SCALE]", help: "set or cycle the price scale",
+		args: fixedArgs([]string{"linear", "log", "percent"}), run: cmdScale},
+	{name: "study", usage: "add|remove SPEC | clear", help: "edit the studies pane, e.g. study add ema 50",
+		args: studyArgs, run: cmdStudy},
+	{name: "export", usage: "[png|svg]", help: "save the visible chart",
+		args: fixedArgs([]string{"png", "svg"}), run: cmdExport},
+}
+
+func fixedArgs(vals []string) func(model, int, []string) []string {
+	return func(_ model, n int, _ []string) []string {
+		if n == 0 {
+			return vals
+		}
+		return nil
+	}
+}
+
+// watchedSymbols completes symbols from the shown watchlist.
+func watchedSymbols(m model, _ int, _ []string) []string {
+	return m.wl.store.Active().Symbols
+}
+
+func studyArgs(m model, n int, before []string) []string {
+	switch {
+	case n == 0:
+		return []string{"add", "remove", "clear"}
+	case n == 1 && before[0] == "add":
+		return []string{"sma", "ema", "bb"}
+	case n == 1 && before[0] == "remove":
+		out := make([]string, len(m.studies))
+		for i, s := range m.studies {
+			out[i] = s.String()
+		}
+		return out
+	}
+	return nil
+}
+
+func cmdSymbol(m model, args []string) (model, tea.Cmd, error) {
+	syms := chart.ParseSymbols(strings.Join(args, " "))
+	if len(syms) == 0 {
+		return m, nil, fmt.Errorf("symbol: name one")
+	}
+	m, cmd := m.load(syms)
+	return m, cmd, nil
+}
+
+func cmdCompare(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 1 && strings.EqualFold(args[0], "off") {
+		m, cmd := m.load([]string{m.symbol})
+		return m, cmd, nil
+	}
+	add := chart.ParseSymbols(strings.Join(args, " "))
+	if len(add) == 0 {
+		return m, nil, fmt.Errorf("compare: name the symbols to add")
+	}
+	m, cmd := m.load(chart.ParseSymbols(m.symbols() + "," + strings.Join(add, ",")))
+	return m, cmd, nil
+}
+
+func cmdRange(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 0 {
+		return m.openPicker(), nil, nil
+	}
+	if len(args) != 1 || !slices.Contains(ranges, strings.ToLower(args[0])) {
+		return m, nil, fmt.Errorf("range: want one of %s", strings.Join(ranges, " "))
+	}
+	m.rng, m.interval, m.status = fitPair(strings.ToLower(args[0]), m.interval, "range")
+	m.vp = newViewport()
+	m.loading = true
+	return m, m.fetch(), nil
+}
+
+func cmdInterval(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 0 {
+		return m.openPicker(), nil, nil
+	}
+	if len(args) != 1 || !slices.Contains(cmdIntervals, strings.ToLower(args[0])) {
+		return m, nil, fmt.Errorf("interval: want one of %s", strings.Join(cmdIntervals, " "))
+	}
+	m.rng, m.interval, m.status = fitPair(m.rng, strings.ToLower(args[0]), "interval")
+	m.vp = newViewport()
+	m.loading = true
+	return m, m.fetch(), nil
+}
+
+func cmdView(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 0 {
+		m, cmd, _ := m.chartKey(actView)
+		return m, cmd, nil
+	}
+	v, ok := chart.ParseViewMode(args[0])
+	if !ok {
+		return m, nil, fmt.Errorf("view: want one of %s", strings.Join(chart.ViewModeNames(), " "))
+	}
+	m.view = v
+	m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	return m, nil, nil
+}
+
+func cmdScale(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 0 {
+		m, cmd, _ := m.chartKey(actScale)
+		return m, cmd, nil
+	}
+	s, ok := chart.ParseScale(args[0])
+	if !ok {
+		return m, nil, fmt.Errorf("scale: want linear, log or percent")
+	}
+	m.scale = s
+	return m, nil, nil
+}
+
+func cmdStudy(m model, args []string) (model, tea.Cmd, error) {
+	if len(args) == 0 {
+		return m, nil, fmt.Errorf("study: add SPEC, remove SPEC or clear")
+	}
+	switch args[0] {
+	case "clear":
+		m.studies = nil
+		m.status = "studies cleared"
+		return m, nil, nil
+	case "add", "remove":
+		spec, err := studies.ParseSpec(strings.Join(args[1:], " "))
+		if err != nil {
+			return m, nil, err
+		}
+		i := slices.Index(m.studies, spec)
+		if args[0] == "add" {
+			if i < 0 {
+				m.studies = append(slices.Clip(m.studies), spec)
+			}
+			m.status = "study " + spec.Label()
+			return m, nil, nil
+		}
+		if i < 0 {
+			return m, nil, fmt.Errorf("study: no %s to remove", spec.Label())
+		}
+		m.studies = slices.Delete(slices.Clone(m.studies), i, i+1)
+		m.status = "removed " + spec.Label()
+		return m, nil, nil
+	}
+	return m, nil, fmt.Errorf("study: unknown %q (want add, remove or clear)", args[0])
+}
+
+func cmdExport(m model, args []string) (model, tea.Cmd, error) {
+	format := "png"
+	if len(args) > 0 {
+		format = strings.ToLower(args[0])
+	}
+	if format != "png" && format != "svg" {
+		return m, nil, fmt.Errorf("export: want png or svg")
+	}
+	if len(m.ticks) == 0 {
+		return m, nil, fmt.Errorf("export: nothing charted yet")
+	}
+	m.status = "exporting…"
+	return m, m.exportCmd(format), nil
+}
+
+// runCommand runs the command line line and records it in the history.
+func (m model) runCommand(line string) (model, tea.Cmd) {
+	line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ":"))
+	if line == "" {
+		return m, nil
+	}
+	m.history = append(slices.DeleteFunc(slices.Clone(m.history), func(h string) bool { return h == line }), line)
+	if len(m.history) > historyLimit {
+		m.history = m.history[len(m.history)-historyLimit:]
+	}
+	f := strings.Fields(line)
+	name, args := strings.ToLower(f[0]), f[1:]
+	for _, c := range commands {
+		if c.name == name {
+			m, cmd, err := c.run(m, args)
+			if err != nil {
+				m.status = err.Error()
+			}
+			return m, cmd
+		}
+	}
+	a := action(name)
+	if _, ok := m.keys.bindings[a]; !ok {
+		m.status = fmt.Sprintf("unknown command %q (tab completes)", name)
+		return m, nil
+	}
+	if len(args) > 0 {
+		m.status = name + " takes no arguments"
+		return m, nil
+	}
+	return m.run([]action{a})
+}
+
+// commandNames lists every command and action name, sorted.
+func (m model) commandNames() []string {
+	var out []string
+	for _, c := range commands {
+		out = append(out, c.name)
+	}
+	for a := range m.keys.bindings {
+		if !slices.Contains(out, string(a)) {
+			out = append(out, string(a))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// completions returns the ways to complete the last word of line, each
+// the whole line completed.
+func (m model) completions(line string) []string {
+	f := strings.Fields(line)
+	if len(f) == 0 || !strings.HasSuffix(line, " ") && len(f) == 1 {
+		word := ""
+		if len(f) == 1 {
+			word = strings.ToLower(f[0])
+		}
+		return withPrefix(m.commandNames(), "", word)
+	}
+	word := ""
+	if !strings.HasSuffix(line, " ") {
+		word, f = f[len(f)-1], f[:len(f)-1]
+	}
+	i := slices.IndexFunc(commands, func(c command) bool { return c.name == strings.ToLower(f[0]) })
+	if i < 0 || commands[i].args == nil {
+		return nil
+	}
+	cands := commands[i].args(m, len(f)-1, f[1:])
+	return withPrefix(cands, strings.Join(f, " ")+" ", word)
+}
+
+// withPrefix returns head+c for each of cands starting with word, case
+// ignored.
+func withPrefix(cands []string, head, word string) []string {
+	var out []string
+	for _, c := range cands {
+		if strings.HasPrefix(strings.ToLower(c), strings.ToLower(word)) {
+			out = append(out, head+c)
+		}
+	}
+	return out
+}
+
+// cmdLine is the state of the : prompt between key presses: the
+// completions being cycled through and the history entry shown.
+type cmdLine struct {
+	matches []string
+	match   int
+	hist    int // len(history) when not browsing
+}
+
+// commandKey handles the : prompt's own keys: tab and shift+tab cycle
+// through completions, up and down through the history. It reports false
+// for any other key, which also ends a completion.
+func (m model) commandKey(key string) (model, bool) {
+	switch key {
+	case "tab", "shift+tab":
+		if m.cmdLine.matches == nil {
+			m.cmdLine.matches = m.completions(m.input.Value())
+			m.cmdLine.match = -1
+			if len(m.cmdLine.matches) == 0 {
+				m.status = "no completions"
+				m.cmdLine.matches = nil
+				return m, true
+			}
+		}
+		n := len(m.cmdLine.matches)
+		step := 1
+		if key == "shift+tab" {
+			step = -1
+		}
+		m.cmdLine.match = ((m.cmdLine.match+step)%n + n) % n
+		value := m.cmdLine.matches[m.cmdLine.match]
+		if n == 1 {
+			// the only completion: ready for the next argument
+			value += " "
+			m.cmdLine.matches = nil
+		}
+		m.input.SetValue(value)
+		m.input.CursorEnd()
+		return m, true
+	case "up", "down":
+		m.cmdLine.matches = nil
+		h := m.cmdLine.hist
+		if key == "up" {
+			h = max(h-1, 0)
+		} else {
+			h = min(h+1, len(m.history))
+		}
+		m.cmdLine.hist = h
+		value := ""
+		if h < len(m.history) {
+			value = m.history[h]
+		}
+		m.input.SetValue(value)
+		m.input.CursorEnd()
+		return m, true
+	}
+	m.cmdLine.matches = nil
+	return m, false
+}
+
+// completionHint lists the completions being cycled through, the current
+// one marked, or describes the command typed, for under the prompt.
+func (m model) completionHint() string {
+	if len(m.cmdLine.matches) < 2 {
+		if c, ok := m.commandHelp(); ok {
+			return c
+		}
+		return ""
+	}
+	parts := make([]string, len(m.cmdLine.matches))
+	for i, s := range m.cmdLine.matches {
+		f := strings.Fields(s)
+		parts[i] = f[len(f)-1]
+		if i == m.cmdLine.match {
+			parts[i] = accentStyle.Render(parts[i])
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// commandHelp describes the command being typed.
+func (m model) commandHelp() (string, bool) {
+	f := strings.Fields(m.input.Value())
+	if len(f) == 0 {
+		return "", false
+	}
+	for _, c := range commands {
+		if c.name == strings.ToLower(f[0]) {
+			return c.name + " " + c.usage + ": " + c.help, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+
+	"ticker-forge/internal/chart/studies"
+	"ticker-forge/internal/watchlist"
+)
+
+func TestCompletions(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(Options{})
+	m.wl.store = watchlist.Store{Lists: []watchlist.List{{Name: "tech", Symbols: []string{"AAPL", "AMD", "MSFT"}}}}
+	ema, err := studies.ParseSpec("ema 50")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.studies = []studies.Spec{ema}
+
+	cases := []struct {
+		line string
+		want []string
+	}{
+		{"sy", []string{"symbol"}},
+		{"SCA", []string{"scale"}},
+		{"tab-n", []string{"tab-new", "tab-next"}},
+		{"symbol ", []string{"symbol AAPL", "symbol AMD", "symbol MSFT"}},
+		{"symbol a", []string{"symbol AAPL", "symbol AMD"}},
+		{"compare AAPL m", []string{"compare AAPL MSFT"}},
+		{"range 1", []string{"range 1d", "range 1mo", "range 1y", "range 10y"}},
+		{"range 1d ", nil}, // range takes one argument
+		{"study ", []string{"study add", "study remove", "study clear"}},
+		{"study add b", []string{"study add bb"}},
+		{"study remove ", []string{"study remove " + ema.String()}},
+		{"grid ", nil},  // actions take no arguments
+		{"bogus ", nil}, // nor do unknown commands
+	}
+	for _, c := range cases {
+		if got := m.completions(c.line); !slices.Equal(got, c.want) {
+			t.Errorf("completions(%q) = %q, want %q", c.line, got, c.want)
+		}
+	}
+}
+
+func TestCommandKeyCycles(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(Options{})
+	m.input.SetValue("export ")
+
+	var got []string
+	for _, key := range []string{"tab", "tab", "tab", "shift+tab"} {
+		m, _ = m.commandKey(key)
+		got = append(got, m.input.Value())
+	}
+	want := []string{"export png", "export svg", "export png", "export svg"}
+	if !slices.Equal(got, want) {
+		t.Errorf("tab cycles %q, want %q", got, want)
+	}
+
+	// a single completion is taken with a space, ready for the next word
+	m, _ = m.commandKey("x")
+	m.input.SetValue("stu")
+	if m, _ = m.commandKey("tab"); m.input.Value() != "study " {
+		t.Errorf("single completion gave %q", m.input.Value())
+	}
+}
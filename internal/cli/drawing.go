@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"slices"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/drawings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pendingDrawing is a trendline or retracement waiting for its second
+// point: T or F pinned from at the crosshair bar.
+type pendingDrawing struct {
+	kind chart.DrawingKind
+	from chart.Tick
+}
+
+type drawingsSavedMsg struct{ err error }
+
+// saveDrawingsCmd stores ds as symbol's drawings.
+func saveDrawingsCmd(symbol string, ds []chart.Drawing) tea.Cmd {
+	return func() tea.Msg {
+		_, err := drawings.Update("", symbol, func([]chart.Drawing) []chart.Drawing { return ds })
+		return drawingsSavedMsg{err}
+	}
+}
+
+// draw handles the drawing actions at the crosshair bar: level adds one at
+// its close, trend and fib pin the first point of a trendline or
+// retracement and complete it on the second press, undo removes the
+// newest drawing.
+func (m model) draw(a action) (model, tea.Cmd) {
+	if len(m.compare) > 1 {
+		m.status = "drawings are per symbol; leave compare mode first"
+		return m, nil
+	}
+	if a == actUndo {
+		if len(m.drawings) == 0 {
+			m.status = "no drawings on " + m.symbol
+			return m, nil
+		}
+		last := m.drawings[len(m.drawings)-1]
+		// clipped so a later append can't write into a slice being saved
+		m.drawings = slices.Clip(m.drawings[:len(m.drawings)-1])
+		m.status = "removed " + last.String()
+		return m, saveDrawingsCmd(m.symbol, m.drawings)
+	}
+
+	c := m.vp.cursor
+	if c < 0 || c >= len(m.ticks) {
+		m.status = "move the crosshair (" + m.keys.keys(actCursorLeft) + "/" + m.keys.keys(actCursorRight) + ") to a bar first"
+		return m, nil
+	}
+	k := m.ticks[c]
+	var d chart.Drawing
+	switch a {
+	case actLevel:
+		d = chart.Drawing{Kind: chart.DrawLevel, From: chart.Anchor{Time: k.T, Price: k.C}}
+	case actTrend, actFib:
+		kind := chart.DrawTrend
+		if a == actFib {
+			kind = chart.DrawFib
+		}
+		if m.pending == nil || m.pending.kind != kind || m.pending.from.T.Equal(k.T) {
+			m.pending = &pendingDrawing{kind: kind, from: k}
+			m.status = string(kind) + " from " + k.T.Format("15:04") + ": move the crosshair and press " + m.keys.keys(a) + " again (" + m.keys.keys(actCancel) + " cancels)"
+			return m, nil
+		}
+		d = anchored(kind, m.pending.from, k)
+		m.pending = nil
+	default:
+		return m, nil
+	}
+	m.drawings = append(slices.Clip(m.drawings), d)
+	m.status = "added " + d.String()
+	return m, saveDrawingsCmd(m.symbol, m.drawings)
+}
+
+// anchored builds a trendline through the closes of bars a and b, or a
+// retracement of the move between them: from a's low to b's high when
+// price rose, from a's high to b's low when it fell.
+func anchored(kind chart.DrawingKind, a, b chart.Tick) chart.Drawing {
+	if a.T.After(b.T) {
+		a, b = b, a
+	}
+	if kind == chart.DrawTrend {
+		return chart.Drawing{Kind: kind, From: chart.Anchor{Time: a.T, Price: a.C}, To: chart.Anchor{Time: b.T, Price: b.C}}
+	}
+	if b.C >= a.C {
+		return chart.Drawing{Kind: kind, From: chart.Anchor{Time: a.T, Price: a.L}, To: chart.Anchor{Time: b.T, Price: b.H}}
+	}
+	return chart.Drawing{Kind: kind, From: chart.Anchor{Time: a.T, Price: a.H}, To: chart.Anchor{Time: b.T, Price: b.L}}
+}
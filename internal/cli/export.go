@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/chart/studies"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ExportOptions configures the export command.
+type ExportOptions struct {
+	Symbol   string
+	Symbols  string // comma-separated, in place of Symbol; two or more draw a comparison
+	Range    string
+	Interval string
+	View     string
+	Scale    string
+	Studies  string // e.g. "sma:20,ema:50"
+	Volume   bool
+	Width    int
+	Height   int
+	Theme    string
+	// Output is the file to write; "-" is stdout. Format ("png" or "svg")
+	// defaults to Output's extension.
+	Output string
+	Format string
+}
+
+// Export fetches one chart and writes it as a PNG or SVG file.
+func Export(opts ExportOptions) error {
+	symbols := chart.ParseSymbols(opts.Symbols)
+	if len(symbols) == 0 {
+		symbols = []string{strings.ToUpper(orDefault(opts.Symbol, "AAPL"))}
+	}
+	opts.Output = orDefault(opts.Output, strings.Join(symbols, "-")+".png")
+	format := strings.ToLower(orDefault(opts.Format, strings.TrimPrefix(filepath.Ext(opts.Output), ".")))
+	if format != "png" && format != "svg" {
+		return fmt.Errorf("export: can't tell the format of %q; use -format png or svg", opts.Output)
+	}
+	view, ok := chart.ParseViewMode(orDefault(opts.View, "candles"))
+	if !ok {
+		return fmt.Errorf("export: unknown view %q", opts.View)
+	}
+	scale, ok := chart.ParseScale(opts.Scale)
+	if !ok {
+		return fmt.Errorf("export: unknown scale %q", opts.Scale)
+	}
+	specs, err := studies.ParseSpecs(opts.Studies)
+	if err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	t, err := loadTheme(opts.Theme)
+	if err != nil {
+		return err
+	}
+	rng, interval := orDefault(opts.Range, "1d"), orDefault(opts.Interval, "1m")
+
+	ic := chart.ImageChart{
+		View: view, Scale: scale, Width: opts.Width, Height: opts.Height,
+		Title:  fmt.Sprintf("%s  %s/%s", symbols[0], rng, interval),
+		Cursor: -1, Theme: t, Volume: opts.Volume, Studies: specs,
+	}
+	var ticks []chart.Tick
+	var series []chart.Series
+	if len(symbols) > 1 {
+		raw, err := chart.FetchCompare(symbols, rng, interval)
+		if err != nil {
+			return err
+		}
+		series = chart.CompareSeries(raw)
+		ic.Title = fmt.Sprintf("%s/%s", rng, interval)
+	} else if ticks, err = chart.FetchIntradayOHLC(symbols[0], rng, interval); err != nil {
+		return err
+	}
+
+	if opts.Output == "-" {
+		return writeChart(os.Stdout, format, ic, ticks, series)
+	}
+	return saveChart(opts.Output, format, ic, ticks, series)
+}
+
+// writeChart encodes series as a comparison when there are several, and
+// ticks otherwise.
+func writeChart(w io.Writer, format string, ic chart.ImageChart, ticks []chart.Tick, series []chart.Series) error {
+	if len(series) > 1 {
+		return ic.EncodeCompare(w, format, series)
+	}
+	return ic.Encode(w, format, ticks)
+}
+
+// saveChart writes the chart to path once it has encoded, so a failure
+// leaves no partial file behind.
+func saveChart(path, format string, ic chart.ImageChart, ticks []chart.Tick, series []chart.Series) error {
+	var buf bytes.Buffer
+	if err := writeChart(&buf, format, ic, ticks, series); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+type exportedMsg struct {
+	path string
+	err  error
+}
+
+// exportCmd saves the visible chart with the studies pane's studies as
+// SYMBOL-YYYYMMDD-HHMMSS.png (or .svg, per format) in the working
+// directory, with the volume panel when it is on.
+func (m model) exportCmd(format string) tea.Cmd {
+	ch := m.chart()
+	start, end := m.vp.window(len(m.ticks), ch.Capacity())
+	ic := chart.ImageChart{
+		View: ch.View, Scale: m.scale, Width: 1280, Height: 720,
+		Title:  fmt.Sprintf("%s  %s/%s", m.symbols(), m.rng, m.interval),
+		Cursor: -1, Theme: m.theme, Studies: m.studies, Volume: m.volume,
+	}
+	ticks := m.ticks[start:end]
+	var series []chart.Series
+	for _, s := range m.series {
+		series = append(series, chart.Series{Name: s.Name, Ticks: s.Ticks[start:end]})
+	}
+	name := strings.ReplaceAll(m.symbols(), ",", "-")
+	path := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), format)
+	return func() tea.Msg {
+		return exportedMsg{path: path, err: saveChart(path, format, ic, ticks, series)}
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"image"
+	"strings"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/termimg"
+)
+
+// imageCache holds the last encoded chart image. View runs after every
+// message, and re-encoding a full-screen Sixel each time is wasteful.
+type imageCache struct {
+	key string
+	seq string
+}
+
+// viewImage lays out header, caption and footer like the ASCII chart and
+// fills the rows in between with the chart drawn by draw as an image.
+func (m model) viewImage(ch chart.ASCIIChart, draw func(chart.ImageChart) image.Image) string {
+	rows := m.imageRows(ch)
+	cols := max(20, m.width-1)
+	ic := chart.ImageChart{
+		View:     ch.View,
+		Scale:    ch.Scale,
+		Width:    cols * m.cellW,
+		Height:   rows * m.cellH,
+		Cursor:   ch.Cursor,
+		Box:      ch.Box,
+		Reversal: ch.Reversal,
+		Theme:    m.theme,
+		Volume:   m.volume,
+	}
+
+	start, end := m.vp.window(len(m.ticks), ch.Capacity())
+	key := fmt.Sprintf("%v|%s|%d|%d|%d|%s", ic, m.graphics, start, end, len(m.series), m.lastFetch)
+	cache := m.imgCache
+	if cache == nil {
+		cache = &imageCache{}
+	}
+	if cache.key != key {
+		seq, err := termimg.Encode(m.graphics, draw(ic), cols, rows)
+		if err != nil {
+			return ch.Header + "\n" + errStyle.Render("image: "+err.Error()) + "\n"
+		}
+		*cache = imageCache{key: key, seq: seq}
+	}
+	return ch.Header + "\n" + ch.Caption + termimg.Block(cache.seq, rows) + "\n" + ch.Footer
+}
+
+// imageRows is how many text rows viewImage gives the image: what the
+// header, caption and footer leave.
+func (m model) imageRows(ch chart.ASCIIChart) int {
+	text := ch.Header + "\n" + ch.Caption + "\n" + ch.Footer
+	return max(4, m.height-strings.Count(text, "\n")-2)
+}
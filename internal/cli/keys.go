@@ -0,0 +1,443 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// action names a TUI command. config.yaml rebinds actions by these names
+// under keys, and the ? overlay lists them.
+type action string
+
+const (
+	actQuit    action = "quit"
+	actHelp    action = "help"
+	actCommand action = "command"
+	actRefresh action = "refresh"
+	actTicker  action = "ticker"
+	actExport  action = "export"
+	actImages  action = "images"
+	actMouse   action = "mouse"
+
+	actIntervalNext action = "interval-next"
+	actIntervalPrev action = "interval-prev"
+	actRange1d      action = "range-1d"
+	actRange5d      action = "range-5d"
+	actPicker       action = "pick-range"
+	actView         action = "view"
+	actScale        action = "scale"
+	actProfile      action = "profile"
+	actPatterns     action = "patterns"
+	actPivots       action = "pivots"
+	actZones        action = "zones"
+	actVolume       action = "volume"
+
+	actCursorLeft  action = "cursor-left"
+	actCursorRight action = "cursor-right"
+	actZoomIn      action = "zoom-in"
+	actZoomOut     action = "zoom-out"
+	actPanLeft     action = "pan-left"
+	actPanRight    action = "pan-right"
+	actReset       action = "reset"
+	actCancel      action = "cancel"
+
+	actLevel action = "level"
+	actTrend action = "trend"
+	actFib   action = "fib"
+	actUndo  action = "undo"
+
+	actTabNext     action = "tab-next"
+	actTabPrev     action = "tab-prev"
+	actTabNew      action = "tab-new"
+	actTabClose    action = "tab-close"
+	actGrid        action = "grid"
+	actAutoRefresh action = "auto-refresh"
+
+	actWatchDown     action = "watch-down"
+	actWatchUp       action = "watch-up"
+	actWatchOpen     action = "watch-open"
+	actWatchMoveDown action = "watch-move-down"
+	actWatchMoveUp   action = "watch-move-up"
+	actWatchAdd      action = "watch-add"
+	actWatchRemove   action = "watch-remove"
+	actWatchPrevList action = "watch-prev-list"
+	actWatchNextList action = "watch-next-list"
+	actWatchNewList  action = "watch-new-list"
+	actWatchDelList  action = "watch-delete-list"
+
+	actFocusNext   action = "focus-next"
+	actFocusPrev   action = "focus-prev"
+	actSplitRight  action = "split-right"
+	actSplitDown   action = "split-down"
+	actSwapPane    action = "swap-pane"
+	actGrowPane    action = "grow-pane"
+	actShrinkPane  action = "shrink-pane"
+	actHidePane    action = "hide-pane"
+	actToggleWatch action = "toggle-watchlist"
+)
+
+// actTab is the action switching to tab n (1-based): "tab-1" … "tab-9".
+func actTab(n int) action { return action(fmt.Sprintf("tab-%d", n)) }
+
+// keyDef is an action's default binding. Keys may be sequences, the
+// presses separated by spaces ("g t").
+type keyDef struct {
+	action action
+	keys   []string
+	help   string
+	group  string
+	hidden bool // shares another action's help line (tab-2 … tab-9)
+}
+
+var keyGroups = []string{"General", "Chart", "View", "Drawings", "Tabs", "Watchlist", "Panes"}
+
+var defaultKeys = func() []keyDef {
+	defs := []keyDef{
+		{actQuit, []string{"q", "ctrl+c"}, "quit", "General", false},
+		{actHelp, []string{"?"}, "help", "General", false},
+		{actCommand, []string{":"}, "command line", "General", false},
+		{actRefresh, []string{"r"}, "refresh", "General", false},
+		{actTicker, []string{"/"}, "change ticker", "General", false},
+		{actExport, []string{"e"}, "export PNG", "General", false},
+		{actImages, []string{"g i"}, "image/text chart", "General", false},
+		{actMouse, []string{"M"}, "mouse on/off", "General", false},
+
+		{actIntervalNext, []string{"i"}, "next interval", "Chart", false},
+		{actIntervalPrev, []string{"I"}, "previous interval", "Chart", false},
+		{actRange1d, []string{"d"}, "1 day", "Chart", false},
+		{actRange5d, []string{"w"}, "5 days", "Chart", false},
+		{actPicker, []string{"t"}, "pick range/interval", "Chart", false},
+		{actView, []string{"c"}, "cycle view", "Chart", false},
+		{actScale, []string{"s"}, "scale", "Chart", false},
+		{actProfile, []string{"p"}, "profile", "Chart", false},
+		{actPatterns, []string{"P"}, "patterns", "Chart", false},
+		{actPivots, []string{"L"}, "pivots", "Chart", false},
+		{actZones, []string{"Z"}, "S/R zones", "Chart", false},
+		{actVolume, []string{"V"}, "volume panel", "Chart", false},
+
+		{actCursorLeft, []string{"left"}, "crosshair left", "View", false},
+		{actCursorRight, []string{"right"}, "crosshair right", "View", false},
+		{actZoomIn, []string{"+", "="}, "zoom in", "View", false},
+		{actZoomOut, []string{"-"}, "zoom out", "View", false},
+		{actPanLeft, []string{"h"}, "pan back", "View", false},
+		{actPanRight, []string{"l"}, "pan forward", "View", false},
+		{actReset, []string{"0"}, "reset view", "View", false},
+		{actCancel, []string{"esc"}, "cancel / reset", "View", false},
+
+		{actLevel, []string{"H"}, "level", "Drawings", false},
+		{actTrend, []string{"T"}, "trendline", "Drawings", false},
+		{actFib, []string{"F"}, "retracement", "Drawings", false},
+		{actUndo, []string{"X"}, "undo drawing", "Drawings", false},
+
+		{actTab(1), []string{"1"}, "go to tab", "Tabs", false},
+	}
+	for n := 2; n <= 9; n++ {
+		defs = append(defs, keyDef{actTab(n), []string{fmt.Sprint(n)}, "go to tab", "Tabs", true})
+	}
+	return append(defs,
+		keyDef{actTabNext, []string{"g t"}, "next tab", "Tabs", false},
+		keyDef{actTabPrev, []string{"g T"}, "previous tab", "Tabs", false},
+		keyDef{actTabNew, []string{"g n"}, "new tab", "Tabs", false},
+		keyDef{actTabClose, []string{"g c"}, "close tab", "Tabs", false},
+		keyDef{actGrid, []string{"G"}, "2×2 grid", "Tabs", false},
+		keyDef{actAutoRefresh, []string{"R"}, "auto refresh", "Tabs", false},
+
+		keyDef{actWatchDown, []string{"j", "down"}, "down", "Watchlist", false},
+		keyDef{actWatchUp, []string{"k", "up"}, "up", "Watchlist", false},
+		keyDef{actWatchOpen, []string{"enter"}, "chart symbol", "Watchlist", false},
+		keyDef{actWatchMoveDown, []string{"J"}, "move down", "Watchlist", false},
+		keyDef{actWatchMoveUp, []string{"K"}, "move up", "Watchlist", false},
+		keyDef{actWatchAdd, []string{"a"}, "add symbols", "Watchlist", false},
+		keyDef{actWatchRemove, []string{"x"}, "remove symbol", "Watchlist", false},
+		keyDef{actWatchPrevList, []string{"["}, "previous list", "Watchlist", false},
+		keyDef{actWatchNextList, []string{"]"}, "next list", "Watchlist", false},
+		keyDef{actWatchNewList, []string{"N"}, "new list", "Watchlist", false},
+		keyDef{actWatchDelList, []string{"D"}, "delete empty list", "Watchlist", false},
+
+		keyDef{actFocusNext, []string{"tab"}, "next pane", "Panes", false},
+		keyDef{actFocusPrev, []string{"shift+tab"}, "previous pane", "Panes", false},
+		keyDef{actSplitRight, []string{"|"}, "split right", "Panes", false},
+		keyDef{actSplitDown, []string{"_"}, "split down", "Panes", false},
+		keyDef{actSwapPane, []string{"o"}, "swap pane", "Panes", false},
+		keyDef{actGrowPane, []string{">"}, "grow pane", "Panes", false},
+		keyDef{actShrinkPane, []string{"<"}, "shrink pane", "Panes", false},
+		keyDef{actHidePane, []string{"ctrl+w"}, "hide pane", "Panes", false},
+		keyDef{actToggleWatch, []string{"W"}, "watchlist on/off", "Panes", false},
+	)
+}()
+
+// keyPresets rebind actions on top of defaultKeys.
+var keyPresets = map[string]map[action][]string{
+	"default": {},
+	"vim": {
+		actCursorLeft:  {"h", "left"},
+		actCursorRight: {"l", "right"},
+		actPanLeft:     {"ctrl+b"},
+		actPanRight:    {"ctrl+f"},
+		actFocusNext:   {"tab", "ctrl+w w"},
+		actFocusPrev:   {"shift+tab", "ctrl+w W"},
+		actSplitRight:  {"|", "ctrl+w v"},
+		actSplitDown:   {"_", "ctrl+w s"},
+		actGrowPane:    {">", "ctrl+w >"},
+		actShrinkPane:  {"<", "ctrl+w <"},
+		actHidePane:    {"ctrl+w c"},
+		actSwapPane:    {"o", "ctrl+w x"},
+	},
+	"emacs": {
+		actQuit:        {"q", "ctrl+c", "ctrl+x ctrl+c"},
+		actTicker:      {"/", "ctrl+s"},
+		actCursorLeft:  {"left", "ctrl+b"},
+		actCursorRight: {"right", "ctrl+f"},
+		actPanLeft:     {"h", "alt+b"},
+		actPanRight:    {"l", "alt+f"},
+		actCancel:      {"esc", "ctrl+g"},
+		actWatchDown:   {"j", "down", "ctrl+n"},
+		actWatchUp:     {"k", "up", "ctrl+p"},
+		actFocusNext:   {"tab", "ctrl+x o"},
+		actSplitRight:  {"|", "ctrl+x 3"},
+		actSplitDown:   {"_", "ctrl+x 2"},
+		actHidePane:    {"ctrl+w", "ctrl+x 0"},
+	},
+}
+
+// keyMap resolves key presses to actions.
+type keyMap struct {
+	defs     []keyDef
+	bindings map[action]key.Binding
+	byKeys   map[string][]action // a full sequence → its actions, in defs order
+	prefixes map[string]bool     // the starts of longer sequences
+}
+
+// newKeyMap applies preset (empty for "default") and then overrides, an
+// action name → keys map, to defaultKeys. It fails on an unknown preset
+// or action; warnings list keys left bound to several actions.
+func newKeyMap(preset string, overrides map[string][]string) (km keyMap, warnings []string, err error) {
+	if preset == "" {
+		preset = "default"
+	}
+	p, ok := keyPresets[strings.ToLower(preset)]
+	if !ok {
+		return keyMap{}, nil, fmt.Errorf("unknown keymap %q (want default, vim or emacs)", preset)
+	}
+	km.defs = slices.Clone(defaultKeys)
+	set := func(a action, keys []string) bool {
+		i := slices.IndexFunc(km.defs, func(d keyDef) bool { return d.action == a })
+		if i < 0 {
+			return false
+		}
+		km.defs[i].keys = keys
+		return true
+	}
+	for a, keys := range p {
+		set(a, keys)
+	}
+	for name, keys := range overrides {
+		if !set(action(name), keys) {
+			return keyMap{}, nil, fmt.Errorf("keys: unknown action %q (? in the TUI lists them)", name)
+		}
+	}
+
+	km.bindings = map[action]key.Binding{}
+	km.byKeys = map[string][]action{}
+	km.prefixes = map[string]bool{}
+	for _, d := range km.defs {
+		km.bindings[d.action] = key.NewBinding(key.WithKeys(d.keys...), key.WithHelp(keyLabel(d.keys), d.help))
+		for _, k := range d.keys {
+			k = strings.Join(strings.Fields(k), " ")
+			km.byKeys[k] = append(km.byKeys[k], d.action)
+			presses := strings.Fields(k)
+			for n := 1; n < len(presses); n++ {
+				km.prefixes[strings.Join(presses[:n], " ")] = true
+			}
+		}
+	}
+	for k, acts := range km.byKeys {
+		if len(acts) > 1 && !contextual(acts) {
+			warnings = append(warnings, fmt.Sprintf("%s is bound to %s", keyLabel([]string{k}), joinActions(acts)))
+		}
+		if km.prefixes[k] {
+			warnings = append(warnings, fmt.Sprintf("%s starts a longer binding, so %s never fires", keyLabel([]string{k}), joinActions(acts)))
+		}
+	}
+	sort.Strings(warnings)
+	return km, warnings, nil
+}
+
+// contextual reports whether acts can share a key: a watchlist action
+// applies while the watchlist shows and the others otherwise.
+func contextual(acts []action) bool {
+	watch := 0
+	for _, a := range acts {
+		if strings.HasPrefix(string(a), "watch-") {
+			watch++
+		}
+	}
+	return watch == 1
+}
+
+func joinActions(acts []action) string {
+	s := make([]string, len(acts))
+	for i, a := range acts {
+		s[i] = string(a)
+	}
+	return strings.Join(s, " and ")
+}
+
+// lookup returns the actions bound to the key sequence seq, or more when
+// seq is only the start of a binding.
+func (km keyMap) lookup(seq string) (acts []action, more bool) {
+	if km.prefixes[seq] {
+		return nil, true
+	}
+	return km.byKeys[seq], false
+}
+
+// keys describes a's keys for help text, e.g. "gt" or "←".
+func (km keyMap) keys(a action) string {
+	return km.bindings[a].Help().Key
+}
+
+// arrowLabels are the arrow keys as help shows them.
+var arrowLabels = map[string]string{"left": "←", "right": "→", "up": "↑", "down": "↓"}
+
+// keyLabel writes keys the way help shows them: arrows as arrows and
+// sequences run together.
+func keyLabel(keys []string) string {
+	out := make([]string, len(keys))
+	for i, k := range keys {
+		presses := strings.Fields(k)
+		for j, p := range presses {
+			if arrow, ok := arrowLabels[p]; ok {
+				presses[j] = arrow
+			}
+		}
+		sep := ""
+		if slices.ContainsFunc(presses, func(p string) bool { return len([]rune(p)) > 1 }) {
+			sep = " "
+		}
+		out[i] = strings.Join(presses, sep)
+	}
+	return strings.Join(out, "/")
+}
+
+// shortHelp renders the bindings of acts on one line, for the header and
+// footer hints.
+func (km keyMap) shortHelp(acts ...action) string {
+	h := help.New()
+	h.Styles.ShortKey = hintStyle.Bold(true)
+	h.Styles.ShortDesc = hintStyle
+	h.Styles.ShortSeparator = hintStyle
+	bs := make([]key.Binding, 0, len(acts))
+	for _, a := range acts {
+		bs = append(bs, km.bindings[a])
+	}
+	return h.ShortHelpView(bs)
+}
+
+// helpView is the ? overlay: every action by group, its keys, what it
+// does and the name config.yaml rebinds it by, then the : commands, in
+// columns filling w.
+func (km keyMap) helpView(w, h int) string {
+	var cols []string
+	for _, g := range keyGroups {
+		lines := []string{titleStyle.Render(g)}
+		for _, d := range km.defs {
+			if d.group != g || d.hidden {
+				continue
+			}
+			keys, name := km.keys(d.action), string(d.action)
+			if d.action == actTab(1) {
+				keys, name = "1…9", "tab-1 … tab-9"
+			}
+			lines = append(lines, fmt.Sprintf("%-10s %-18s %s", keys, d.help, subtle.Render(name)))
+		}
+		cols = append(cols, lipgloss.NewStyle().PaddingRight(3).Render(strings.Join(lines, "\n")))
+	}
+	lines := []string{titleStyle.Render("Commands") + subtle.Render("  (every action name runs too)")}
+	for _, c := range commands {
+		lines = append(lines, fmt.Sprintf(":%-9s %-24s %s", c.name, c.usage, subtle.Render(c.help)))
+	}
+	cols = append(cols, strings.Join(lines, "\n"))
+	// as many columns per row as fit
+	var rows []string
+	for len(cols) > 0 {
+		n, width := 0, 0
+		for n < len(cols) && (n == 0 || width+lipgloss.Width(cols[n]) <= w-4) {
+			width += lipgloss.Width(cols[n])
+			n++
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cols[:n]...))
+		cols = cols[n:]
+	}
+	body := strings.Join(rows, "\n\n") + "\n\n" +
+		hintStyle.Render("rebind in config.yaml: keymap: default|vim|emacs, keys: {refresh: [r, f5]} • any key closes")
+	box := paneFocused.Padding(0, 1).Render(body)
+	return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, box)
+}
+
+// tabNumber returns n for the action tab-n.
+func tabNumber(a action) (int, bool) {
+	var n int
+	if _, err := fmt.Sscanf(string(a), "tab-%d", &n); err != nil || n < 1 || n > 9 {
+		return 0, false
+	}
+	return n, true
+}
+
+// run runs the first of acts that applies: the layout's while panes are
+// shown, then the watchlist's while it is, then the chart's.
+func (m model) run(acts []action) (model, tea.Cmd) {
+	var handlers []func(model, action) (model, tea.Cmd, bool)
+	if m.usePanes() {
+		handlers = append(handlers, model.layoutKey)
+	}
+	if m.showWatchlist() {
+		handlers = append(handlers, model.watchKey)
+	}
+	handlers = append(handlers, model.chartKey)
+	for _, h := range handlers {
+		for _, a := range acts {
+			if next, cmd, ok := h(m, a); ok {
+				return next, cmd
+			}
+		}
+	}
+	return m, nil
+}
+
+// key resolves a key press, with the presses of a sequence before it,
+// to actions and runs them.
+func (m model) key(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.help {
+		m.help = false
+		return m, nil
+	}
+	if m.picker.open {
+		return m.pickerKey(msg.String())
+	}
+	seq := msg.String()
+	if m.prefix != "" {
+		seq = m.prefix + " " + seq
+	}
+	acts, more := m.keys.lookup(seq)
+	if more {
+		m.prefix = seq
+		return m, nil
+	}
+	started := m.prefix != ""
+	m.prefix = ""
+	if len(acts) == 0 {
+		if started && msg.String() != "esc" {
+			m.status = "unknown key " + keyLabel([]string{seq}) + " (? lists them)"
+		}
+		return m, nil
+	}
+	return m.run(acts)
+}
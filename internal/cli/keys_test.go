@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestKeyPresets(t *testing.T) {
+	cases := []struct {
+		preset string
+		seq    string
+		want   []action
+	}{
+		{"", "q", []action{actQuit}},
+		{"default", "g t", []action{actTabNext}},
+		{"VIM", "ctrl+w v", []action{actSplitRight}},
+		{"vim", "ctrl+b", []action{actPanLeft}},
+		{"emacs", "ctrl+x ctrl+c", []action{actQuit}},
+		{"emacs", "j", []action{actWatchDown}},
+		{"emacs", "alt+f", []action{actPanRight}},
+	}
+	for _, c := range cases {
+		t.Run(c.preset+" "+c.seq, func(t *testing.T) {
+			km, warnings, err := newKeyMap(c.preset, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(warnings) > 0 {
+				t.Errorf("preset conflicts: %v", warnings)
+			}
+			if got, _ := km.lookup(c.seq); !slices.Equal(got, c.want) {
+				t.Errorf("lookup(%q) = %v, want %v", c.seq, got, c.want)
+			}
+		})
+	}
+	if _, _, err := newKeyMap("nano", nil); err == nil {
+		t.Error("unknown preset accepted")
+	}
+}
+
+func TestKeySequences(t *testing.T) {
+	km, _, err := newKeyMap("vim", map[string][]string{"refresh": {"  z   z "}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		seq  string
+		want []action
+		more bool
+	}{
+		{"g", nil, true},
+		{"g i", []action{actImages}, false},
+		{"g x", nil, false},
+		{"ctrl+w", nil, true},
+		{"ctrl+w c", []action{actHidePane}, false},
+		{"z", nil, true},
+		{"z z", []action{actRefresh}, false}, // spacing in config doesn't matter
+		{"r", nil, false},                    // rebound away
+	}
+	for _, c := range cases {
+		got, more := km.lookup(c.seq)
+		if !slices.Equal(got, c.want) || more != c.more {
+			t.Errorf("lookup(%q) = %v, %v; want %v, %v", c.seq, got, more, c.want, c.more)
+		}
+	}
+}
+
+func TestKeyConflicts(t *testing.T) {
+	cases := []struct {
+		name      string
+		overrides map[string][]string
+		want      []string
+		err       bool
+	}{
+		{"shared with a watchlist key", map[string][]string{"refresh": {"j"}}, nil, false},
+		{"two global actions", map[string][]string{"refresh": {"q"}}, []string{"q is bound to quit and refresh"}, false},
+		{"prefix of a sequence", map[string][]string{"refresh": {"g"}}, []string{"g starts a longer binding, so refresh never fires"}, false},
+		{"unknown action", map[string][]string{"launch": {"x"}}, nil, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, warnings, err := newKeyMap("", c.overrides)
+			if (err != nil) != c.err {
+				t.Fatalf("err = %v, want error %v", err, c.err)
+			}
+			if !slices.Equal(warnings, c.want) {
+				t.Errorf("warnings %q, want %q", warnings, c.want)
+			}
+		})
+	}
+}
+
+func TestKeyLabel(t *testing.T) {
+	cases := []struct {
+		keys []string
+		want string
+	}{
+		{[]string{"left", "h"}, "←/h"},
+		{[]string{"g t"}, "gt"},
+		{[]string{"ctrl+w v"}, "ctrl+w v"},
+		{[]string{"pgdown", "down"}, "pgdown/↓"},
+		{[]string{"shift+up"}, "shift+up"},
+	}
+	for _, c := range cases {
+		if got := keyLabel(c.keys); got != c.want {
+			t.Errorf("keyLabel(%q) = %q, want %q", c.keys, got, c.want)
+		}
+	}
+}
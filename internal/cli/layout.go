@@ -0,0 +1,416 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+
+	"ticker-forge/internal/cfg"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The panes a layout can hold; each appears at most once.
+const (
+	paneWatchlist = "watchlist"
+	paneChart     = "chart"
+	paneQuote     = "quote"
+	paneStudies   = "studies"
+	paneNews      = "news"
+	paneLog       = "log"
+)
+
+var allPanes = []string{paneWatchlist, paneChart, paneQuote, paneStudies, paneNews, paneLog}
+
+const (
+	splitRow    = "row"    // children side by side
+	splitColumn = "column" // children stacked
+)
+
+// defaultLayout is the watchlist left of the chart.
+func defaultLayout() cfg.Layout {
+	return cfg.Layout{Split: splitRow, Children: []cfg.Layout{
+		{Pane: paneWatchlist, Size: 3},
+		{Pane: paneChart, Size: 7},
+	}}
+}
+
+// cleanLayout drops unknown and repeated panes and empty splits from l,
+// collapses splits left with one child and falls back to defaultLayout
+// when no chart is left.
+func cleanLayout(l *cfg.Layout) cfg.Layout {
+	if l == nil {
+		return defaultLayout()
+	}
+	seen := map[string]bool{}
+	var clean func(l cfg.Layout) (cfg.Layout, bool)
+	clean = func(l cfg.Layout) (cfg.Layout, bool) {
+		l.Size = min(max(l.Size, 1), maxPaneSize)
+		if l.Split == "" {
+			if !slices.Contains(allPanes, l.Pane) || seen[l.Pane] {
+				return l, false
+			}
+			seen[l.Pane] = true
+			return cfg.Layout{Pane: l.Pane, Size: l.Size}, true
+		}
+		if l.Split != splitColumn {
+			l.Split = splitRow
+		}
+		var kids []cfg.Layout
+		for _, c := range l.Children {
+			if c, ok := clean(c); ok {
+				kids = append(kids, c)
+			}
+		}
+		switch len(kids) {
+		case 0:
+			return l, false
+		case 1:
+			kids[0].Size = l.Size
+			return kids[0], true
+		}
+		return cfg.Layout{Split: l.Split, Size: l.Size, Children: kids}, true
+	}
+	out, ok := clean(*l)
+	if !ok || !seen[paneChart] {
+		return defaultLayout()
+	}
+	return out
+}
+
+// maxPaneSize caps Size so that growing a pane stays undoable in a few
+// presses.
+const maxPaneSize = 20
+
+// cloneLayout returns a deep copy of l.
+func cloneLayout(l cfg.Layout) cfg.Layout {
+	kids := make([]cfg.Layout, len(l.Children))
+	for i, c := range l.Children {
+		kids[i] = cloneLayout(c)
+	}
+	l.Children = kids
+	if len(kids) == 0 {
+		l.Children = nil
+	}
+	return l
+}
+
+// panesOf lists the panes of l in reading order: the order Tab walks.
+func panesOf(l cfg.Layout) []string {
+	if l.Split == "" {
+		return []string{l.Pane}
+	}
+	var out []string
+	for _, c := range l.Children {
+		out = append(out, panesOf(c)...)
+	}
+	return out
+}
+
+// findPane returns the split holding pane and its index there; parent is
+// nil when l is pane itself.
+func findPane(l *cfg.Layout, pane string) (parent *cfg.Layout, i int) {
+	for i := range l.Children {
+		c := &l.Children[i]
+		if c.Split == "" && c.Pane == pane {
+			return l, i
+		}
+		if p, j := findPane(c, pane); p != nil {
+			return p, j
+		}
+	}
+	return nil, -1
+}
+
+// splitPane puts pane next to at, beside it (splitRow) or below it
+// (splitColumn), sharing at's space.
+func splitPane(l *cfg.Layout, at, pane, dir string) {
+	leaf := cfg.Layout{Pane: pane, Size: 5}
+	parent, i := findPane(l, at)
+	if parent == nil { // at is the whole layout
+		*l = cfg.Layout{Split: dir, Children: []cfg.Layout{{Pane: at, Size: 5}, leaf}}
+		return
+	}
+	if parent.Split == dir {
+		leaf.Size = parent.Children[i].Size
+		parent.Children = slices.Insert(parent.Children, i+1, leaf)
+		return
+	}
+	old := parent.Children[i]
+	parent.Children[i] = cfg.Layout{Split: dir, Size: old.Size, Children: []cfg.Layout{{Pane: at, Size: 5}, leaf}}
+}
+
+// removePane takes pane out of l, collapsing a split left with one child.
+func removePane(l *cfg.Layout, pane string) {
+	parent, i := findPane(l, pane)
+	if parent == nil {
+		return
+	}
+	parent.Children = slices.Delete(parent.Children, i, i+1)
+	*l = cleanLayout(l)
+}
+
+// resizePane grows (delta > 0) or shrinks pane within its split.
+func resizePane(l *cfg.Layout, pane string, delta int) bool {
+	parent, i := findPane(l, pane)
+	if parent == nil {
+		return false
+	}
+	c := &parent.Children[i]
+	size := min(max(c.Size+delta, 1), maxPaneSize)
+	if size == c.Size {
+		return false
+	}
+	c.Size = size
+	return true
+}
+
+// share splits total cells between children by their sizes, giving the
+// remainder to the last.
+func share(total int, kids []cfg.Layout) []int {
+	sum := 0
+	for _, c := range kids {
+		sum += max(c.Size, 1)
+	}
+	out := make([]int, len(kids))
+	left := total
+	for i, c := range kids {
+		if i == len(kids)-1 {
+			out[i] = left
+			break
+		}
+		out[i] = total * max(c.Size, 1) / sum
+		left -= out[i]
+	}
+	return out
+}
+
+// placePanes calls visit with every pane of l and the size of its content
+// when l fills w×h cells, borders taken off.
+func placePanes(l cfg.Layout, w, h int, visit func(pane string, w, h int)) {
+	paneRects(l, 0, 0, w, h, func(pane string, _, _, w, h int) {
+		visit(pane, max(w-2, 1), max(h-2, 1))
+	})
+}
+
+// paneRects calls visit with every pane of l and the cells it takes,
+// borders included, when l fills the w×h cells from x, y.
+func paneRects(l cfg.Layout, x, y, w, h int, visit func(pane string, x, y, w, h int)) {
+	if l.Split == "" {
+		visit(l.Pane, x, y, w, h)
+		return
+	}
+	total := w
+	if l.Split == splitColumn {
+		total = h
+	}
+	for i, n := range share(total, l.Children) {
+		if l.Split == splitColumn {
+			paneRects(l.Children[i], x, y, w, n, visit)
+			y += n
+		} else {
+			paneRects(l.Children[i], x, y, n, h, visit)
+			x += n
+		}
+	}
+}
+
+// paneSize returns the content size of pane in the current layout, false
+// when the layout doesn't show it.
+func (m model) paneSize(pane string) (w, h int, ok bool) {
+	placePanes(m.layout, m.width, m.height, func(p string, pw, ph int) {
+		if p == pane {
+			w, h, ok = pw, ph, true
+		}
+	})
+	return w, h, ok
+}
+
+// usePanes reports whether View lays out panes: the terminal size is
+// known and the chart is drawn as text (images are placed by the
+// terminal, so they keep the whole screen).
+func (m model) usePanes() bool {
+	return m.width > 0 && m.height > 0 && !m.images
+}
+
+// shows reports whether the layout holds pane.
+func (m model) shows(pane string) bool {
+	return slices.Contains(panesOf(m.layout), pane)
+}
+
+var (
+	paneBorder  = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240"))
+	paneFocused = paneBorder.BorderForeground(lipgloss.Color("205"))
+)
+
+// renderLayout draws l in w×h cells.
+func (m model) renderLayout(l cfg.Layout, w, h int) string {
+	if l.Split == "" {
+		cw, ch := max(w-2, 1), max(h-2, 1)
+		body := lipgloss.NewStyle().MaxWidth(cw).MaxHeight(ch).Render(m.paneView(l.Pane, cw, ch))
+		style := paneBorder
+		if l.Pane == m.focus {
+			style = paneFocused
+		}
+		return style.Width(cw).Height(ch).Render(body)
+	}
+	total := w
+	if l.Split == splitColumn {
+		total = h
+	}
+	parts := make([]string, len(l.Children))
+	for i, n := range share(total, l.Children) {
+		if l.Split == splitColumn {
+			parts[i] = m.renderLayout(l.Children[i], w, n)
+		} else {
+			parts[i] = m.renderLayout(l.Children[i], n, h)
+		}
+	}
+	if l.Split == splitColumn {
+		return lipgloss.JoinVertical(lipgloss.Left, parts...)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+}
+
+// paneView draws the content of pane in w×h cells.
+func (m model) paneView(pane string, w, h int) string {
+	switch pane {
+	case paneWatchlist:
+		return m.watchlistPane(w, h)
+	case paneChart:
+		if m.grid {
+			return m.viewGrid(w, h)
+		}
+		return m.viewChart()
+	case paneQuote:
+		return m.quotePane(w)
+	case paneStudies:
+		return m.studiesPane(w)
+	case paneNews:
+		return m.newsPane(w, h)
+	case paneLog:
+		return m.logPane(w, h)
+	}
+	return ""
+}
+
+type layoutSavedMsg struct{ err error }
+
+// saveLayoutCmd stores a copy of l, taken now, in config.yaml.
+func saveLayoutCmd(l cfg.Layout) tea.Cmd {
+	l = cloneLayout(l)
+	return func() tea.Msg {
+		return layoutSavedMsg{cfg.Update(func(c *cfg.Config) { c.Layout = &l })}
+	}
+}
+
+// layoutKey handles the layout actions: move the focus, split the focused
+// pane beside or below it with a pane not shown yet, swap it for the next
+// one not shown, grow, shrink or hide it, and show or hide the watchlist.
+// It reports false for any other action.
+func (m model) layoutKey(a action) (model, tea.Cmd, bool) {
+	panes := panesOf(m.layout)
+	i := slices.Index(panes, m.focus)
+	switch a {
+	case actFocusNext, actFocusPrev:
+		step := 1
+		if a == actFocusPrev {
+			step = -1
+		}
+		m.focus = panes[((i+step)%len(panes)+len(panes))%len(panes)]
+		return m, nil, true
+	case actSplitRight, actSplitDown:
+		next := m.hiddenPane(m.focus)
+		if next == "" {
+			m.status = "every pane is shown"
+			return m, nil, true
+		}
+		dir := splitRow
+		if a == actSplitDown {
+			dir = splitColumn
+		}
+		splitPane(&m.layout, m.focus, next, dir)
+		m.focus = next
+	case actSwapPane:
+		if m.focus == paneChart {
+			m.status = "the chart pane stays"
+			return m, nil, true
+		}
+		next := m.hiddenPane(m.focus)
+		if next == "" {
+			m.status = "every pane is shown"
+			return m, nil, true
+		}
+		if parent, j := findPane(&m.layout, m.focus); parent != nil {
+			parent.Children[j].Pane = next
+		} else {
+			m.layout.Pane = next
+		}
+		m.focus = next
+	case actGrowPane, actShrinkPane:
+		delta := 1
+		if a == actShrinkPane {
+			delta = -1
+		}
+		if !resizePane(&m.layout, m.focus, delta) {
+			return m, nil, true
+		}
+	case actHidePane:
+		if m.focus == paneChart {
+			m.status = "the chart pane stays"
+			return m, nil, true
+		}
+		removePane(&m.layout, m.focus)
+		m.focus = paneChart
+	case actToggleWatch:
+		if m.shows(paneWatchlist) {
+			removePane(&m.layout, paneWatchlist)
+			if m.focus == paneWatchlist {
+				m.focus = paneChart
+			}
+			break
+		}
+		m.layout = cfg.Layout{Split: splitRow, Children: []cfg.Layout{{Pane: paneWatchlist, Size: 3}, withSize(m.layout, 7)}}
+		m.layout = cleanLayout(&m.layout)
+	default:
+		return m, nil, false
+	}
+	m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	cmds := []tea.Cmd{saveLayoutCmd(m.layout)}
+	if m.shows(paneWatchlist) {
+		cmds = append(cmds, quotesCmd(m.missingQuotes(), false))
+	}
+	return m, tea.Batch(cmds...), true
+}
+
+func withSize(l cfg.Layout, size int) cfg.Layout {
+	l.Size = size
+	return l
+}
+
+// hiddenPane returns the first pane after after (in allPanes order,
+// wrapping) that the layout doesn't show; "" when it shows them all.
+func (m model) hiddenPane(after string) string {
+	shown := panesOf(m.layout)
+	i := slices.Index(allPanes, after)
+	for k := 1; k <= len(allPanes); k++ {
+		if p := allPanes[(i+k)%len(allPanes)]; !slices.Contains(shown, p) {
+			return p
+		}
+	}
+	return ""
+}
+
+// paneTitle is the first line of a pane.
+func paneTitle(s string, w int) string {
+	return titleStyle.Render(truncate(s, w))
+}
+
+// fitLines keeps the last n of lines after the title, for panes that
+// show the newest entries at the bottom.
+func fitLines(title string, lines []string, n int) string {
+	if len(lines) > n-1 {
+		lines = lines[len(lines)-(n-1):]
+	}
+	return strings.Join(append([]string{title}, lines...), "\n")
+}
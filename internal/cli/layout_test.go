@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"ticker-forge/internal/cfg"
+)
+
+// layoutString writes l as "split:size(child child)" with panes as
+// "pane:size", for comparing layouts at a glance.
+func layoutString(l cfg.Layout) string {
+	if l.Split == "" {
+		return fmt.Sprintf("%s:%d", l.Pane, l.Size)
+	}
+	kids := make([]string, len(l.Children))
+	for i, c := range l.Children {
+		kids[i] = layoutString(c)
+	}
+	return fmt.Sprintf("%s:%d(%s)", l.Split, l.Size, strings.Join(kids, " "))
+}
+
+func leaf(pane string, size int) cfg.Layout { return cfg.Layout{Pane: pane, Size: size} }
+
+func split(dir string, size int, kids ...cfg.Layout) cfg.Layout {
+	return cfg.Layout{Split: dir, Size: size, Children: kids}
+}
+
+func TestCleanLayout(t *testing.T) {
+	cases := []struct {
+		name string
+		in   *cfg.Layout
+		want string
+	}{
+		{"none", nil, "row:0(watchlist:3 chart:7)"},
+		{"unknown and repeated panes", &cfg.Layout{Split: splitRow, Children: []cfg.Layout{
+			leaf(paneChart, 5), leaf("ticker", 2), leaf(paneChart, 2), leaf(paneQuote, 0),
+		}}, "row:1(chart:5 quote:1)"},
+		{"lone children collapse", &cfg.Layout{Split: splitColumn, Size: 4, Children: []cfg.Layout{
+			split(splitRow, 2, leaf(paneChart, 7)),
+			split(splitColumn, 3),
+		}}, "chart:4"},
+		{"unknown split and oversize", &cfg.Layout{Split: "grid", Children: []cfg.Layout{
+			leaf(paneChart, 99), leaf(paneLog, -2),
+		}}, "row:1(chart:20 log:1)"},
+		{"no chart", &cfg.Layout{Split: splitRow, Children: []cfg.Layout{
+			leaf(paneQuote, 1), leaf(paneNews, 1),
+		}}, "row:0(watchlist:3 chart:7)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := layoutString(cleanLayout(c.in)); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestSplitAndRemovePane(t *testing.T) {
+	cases := []struct {
+		name string
+		in   cfg.Layout
+		edit func(*cfg.Layout)
+		want string
+	}{
+		{"split the only pane", leaf(paneChart, 0),
+			func(l *cfg.Layout) { splitPane(l, paneChart, paneQuote, splitRow) },
+			"row:0(chart:5 quote:5)"},
+		{"split along the parent", defaultLayout(),
+			func(l *cfg.Layout) { splitPane(l, paneChart, paneQuote, splitRow) },
+			"row:0(watchlist:3 chart:7 quote:7)"},
+		{"split across the parent", defaultLayout(),
+			func(l *cfg.Layout) { splitPane(l, paneChart, paneQuote, splitColumn) },
+			"row:0(watchlist:3 column:7(chart:5 quote:5))"},
+		{"remove collapses the split", split(splitRow, 0, leaf(paneWatchlist, 3),
+			split(splitColumn, 7, leaf(paneChart, 5), leaf(paneQuote, 5))),
+			func(l *cfg.Layout) { removePane(l, paneQuote) },
+			"row:1(watchlist:3 chart:7)"},
+		{"remove down to the chart", defaultLayout(),
+			func(l *cfg.Layout) { removePane(l, paneWatchlist) },
+			"chart:1"},
+		{"remove a pane not shown", defaultLayout(),
+			func(l *cfg.Layout) { removePane(l, paneNews) },
+			"row:0(watchlist:3 chart:7)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			l := cloneLayout(c.in)
+			c.edit(&l)
+			if got := layoutString(l); got != c.want {
+				t.Errorf("got %s, want %s", got, c.want)
+			}
+		})
+	}
+}
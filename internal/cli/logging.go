@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ticker-forge/internal/cfg"
+)
+
+// openLog builds the handler for the log: text records at level and
+// above, written to file, or when file is empty to ticker-forge.log in
+// the config directory in TUI mode (the terminal belongs to the TUI) and
+// to stderr otherwise. closeFn closes the file.
+func openLog(mode Mode, level, file string) (h slog.Handler, closeFn func() error, err error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(orDefault(level, "info"))); err != nil {
+		return nil, nil, fmt.Errorf("log level %q: use debug, info, warn or error", level)
+	}
+	var w io.Writer = os.Stderr
+	closeFn = func() error { return nil }
+	if file == "" && mode == ModeTUI {
+		dir, err := cfg.Dir()
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, nil, err
+		}
+		file = filepath.Join(dir, "ticker-forge.log")
+	}
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		w, closeFn = f, f.Close
+	}
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: lvl}), closeFn, nil
+}
+
+// paneHandler copies each record to the log pane's buffer as one line
+// before passing it on to next, which also decides the level. Attrs
+// inside groups are keyed "group.key", as the text handler writes them.
+type paneHandler struct {
+	buf   *logBuffer
+	attrs string // " key=value" for each attr added by WithAttrs
+	group string // "name." for each group opened by WithGroup
+	next  slog.Handler
+}
+
+func (h paneHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h paneHandler) Handle(ctx context.Context, r slog.Record) error {
+	var b strings.Builder
+	if r.Level >= slog.LevelWarn {
+		b.WriteString(r.Level.String() + " ")
+	}
+	b.WriteString(r.Message + h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		b.WriteString(h.attr(a))
+		return true
+	})
+	h.buf.add(r.Time, b.String())
+	return h.next.Handle(ctx, r)
+}
+
+func (h paneHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	for _, a := range attrs {
+		h.attrs += h.attr(a)
+	}
+	h.next = h.next.WithAttrs(attrs)
+	return h
+}
+
+func (h paneHandler) WithGroup(name string) slog.Handler {
+	if name != "" {
+		h.group += name + "."
+	}
+	h.next = h.next.WithGroup(name)
+	return h
+}
+
+// attr writes a as " key=value" inside the handler's groups.
+func (h paneHandler) attr(a slog.Attr) string {
+	a.Key = h.group + a.Key
+	return " " + a.String()
+}
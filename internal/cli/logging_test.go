@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestPaneHandler(t *testing.T) {
+	var out bytes.Buffer
+	buf := &logBuffer{}
+	next := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo})
+	l := slog.New(paneHandler{buf: buf, next: next})
+
+	l.Debug("hidden")
+	l.With("tab", 2).Info("fetched", "bars", 390)
+	l.WithGroup("req").With("symbol", "AAPL").WithGroup("").Warn("slow", "took", "2s")
+
+	want := []string{
+		"fetched tab=2 bars=390",
+		"WARN slow req.symbol=AAPL req.took=2s",
+	}
+	got := buf.recent()
+	if len(got) != len(want) {
+		t.Fatalf("pane lines %q, want %q", got, want)
+	}
+	for i, line := range got {
+		// drop the time
+		if _, rest, _ := strings.Cut(line, " "); rest != want[i] {
+			t.Errorf("line %d = %q, want %q", i, rest, want[i])
+		}
+	}
+	if text := out.String(); !strings.Contains(text, "req.symbol=AAPL req.took=2s") || strings.Contains(text, "hidden") {
+		t.Errorf("text handler wrote %q", text)
+	}
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"strings"
+
+	"ticker-forge/internal/chart"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// dragState is a left-button drag panning the chart: the column the
+// button is over, as of the last pan.
+type dragState struct {
+	active bool
+	x      int
+}
+
+// mouse handles a mouse event: a click focuses the pane under it, then
+// the pane handles the event. Overlays and prompts ignore the mouse.
+func (m model) mouse(msg tea.MouseMsg) (model, tea.Cmd) {
+	if !m.mouseOn || m.help || m.picker.open || m.inputMode {
+		return m, nil
+	}
+	if msg.Action == tea.MouseActionRelease {
+		m.drag.active = false
+		return m, nil
+	}
+	pane, x, y, w, h := m.paneAt(msg.X, msg.Y)
+	if pane == "" {
+		return m, nil
+	}
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft && m.usePanes() {
+		m.focus = pane
+	}
+	switch pane {
+	case paneWatchlist:
+		return m.watchMouse(msg, y, h)
+	case paneChart:
+		if m.grid {
+			return m.gridMouse(msg, x, y, w, h), nil
+		}
+		return m.chartMouse(msg, x, y)
+	}
+	return m, nil
+}
+
+// paneAt returns the pane under screen cell x, y, the cell's position in
+// the pane's content and the content's size.
+func (m model) paneAt(x, y int) (pane string, cx, cy, w, h int) {
+	if !m.usePanes() {
+		return paneChart, x, y, m.width, m.height
+	}
+	paneRects(m.layout, 0, 0, m.width, m.height, func(p string, px, py, pw, ph int) {
+		if x >= px && x < px+pw && y >= py && y < py+ph {
+			pane, cx, cy, w, h = p, x-px-1, y-py-1, max(pw-2, 1), max(ph-2, 1)
+		}
+	})
+	return pane, cx, cy, w, h
+}
+
+// watchMouse charts the watchlist row clicked; the wheel moves the
+// selection.
+func (m model) watchMouse(msg tea.MouseMsg, y, rows int) (model, tea.Cmd) {
+	switch {
+	case msg.Button == tea.MouseButtonWheelUp:
+		m, cmd, _ := m.watchKey(actWatchUp)
+		return m, cmd
+	case msg.Button == tea.MouseButtonWheelDown:
+		m, cmd, _ := m.watchKey(actWatchDown)
+		return m, cmd
+	case msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress:
+		return m, nil
+	}
+	// the rows watchlistPane draws under its title
+	l := m.wl.store.Active()
+	i := max(0, m.wl.sel-(rows-3)) + y - 1
+	if y < 1 || i >= len(l.Symbols) {
+		return m, nil
+	}
+	m.wl.sel = i
+	m, cmd, _ := m.watchKey(actWatchOpen)
+	return m, cmd
+}
+
+// gridMouse makes the grid cell clicked the active tab.
+func (m model) gridMouse(msg tea.MouseMsg, x, y, w, h int) model {
+	if msg.Button != tea.MouseButtonLeft || msg.Action != tea.MouseActionPress {
+		return m
+	}
+	cw, ch := gridSize(w, h)
+	col, row := min(x/(cw+2), 1), min(y/(ch+2), 1)
+	return m.switchTab(m.tab/4*4 + row*2 + col)
+}
+
+// chartMouse handles the chart pane: the wheel zooms, hovering over the
+// plot moves the crosshair, dragging it pans, and clicking a key hint
+// runs its action.
+func (m model) chartMouse(msg tea.MouseMsg, x, y int) (model, tea.Cmd) {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp, tea.MouseButtonWheelDown:
+		m.vp.zoom(msg.Button == tea.MouseButtonWheelUp, len(m.ticks), m.chart().Capacity())
+		return m, nil
+	}
+	click := msg.Button == tea.MouseButtonLeft && msg.Action == tea.MouseActionPress
+	if m.err != nil || m.loading || len(m.ticks) < 2 {
+		// only the header is drawn
+		if click {
+			return m.clickHint(m.chartHeader(), x, y)
+		}
+		return m, nil
+	}
+
+	ch, start, end := m.framedChart()
+	top, rows, footer := m.chartRows(ch)
+	if y < top || y >= top+rows {
+		switch {
+		case !click:
+		case y < top:
+			return m.clickHint(ch.Header+"\n"+ch.Caption, x, y)
+		case y >= footer:
+			return m.clickHint(ch.Footer, x, y-footer)
+		}
+		return m, nil
+	}
+
+	n, capacity := len(m.ticks), ch.Capacity()
+	bar := ch.BarAt(x, end-start)
+	switch {
+	case click:
+		m.drag = dragState{active: true, x: x}
+	case msg.Action == tea.MouseActionMotion && msg.Button == tea.MouseButtonLeft && m.drag.active:
+		// dragging right brings older bars into view
+		from := ch.BarAt(m.drag.x, end-start)
+		if delta := bar - from; bar >= 0 && from >= 0 && delta != 0 {
+			m.vp.pan(delta, n, capacity)
+			m.drag.x = x
+		}
+		return m, nil
+	case msg.Action != tea.MouseActionMotion:
+		return m, nil
+	}
+	if bar >= 0 {
+		m.vp.cursor = start + bar
+	}
+	return m, nil
+}
+
+// chartRows returns where viewChart draws ch's plot: its first line, how
+// many lines it takes (none for an image, which has no bars to point at)
+// and the first line of the footer.
+func (m model) chartRows(ch chart.ASCIIChart) (top, rows, footer int) {
+	if m.images {
+		top = strings.Count(ch.Header, "\n") + strings.Count(ch.Caption, "\n") + 2
+		return top, 0, top + m.imageRows(ch)
+	}
+	top, rows = ch.PlotRows(len(m.series) > 1)
+	return top, rows, top + rows + 1 // after a blank line
+}
+
+// clickHint runs the action of the key hint at column x of line y of
+// text.
+func (m model) clickHint(text string, x, y int) (model, tea.Cmd) {
+	lines := strings.Split(ansi.Strip(text), "\n")
+	if y < 0 || y >= len(lines) {
+		return m, nil
+	}
+	if a, ok := m.hintAt(lines[y], x); ok {
+		return m.run([]action{a})
+	}
+	return m, nil
+}
+
+// hintAt returns the action whose key hint ("r refresh") covers column x
+// of line, a header or footer line stripped of color.
+func (m model) hintAt(line string, x int) (action, bool) {
+	for _, hints := range [][]action{headerHints, m.footerHints()} {
+		from := 0
+		for _, a := range hints {
+			h := m.keys.bindings[a].Help()
+			label := h.Key + " " + h.Desc
+			i := strings.Index(line[from:], label)
+			if i < 0 {
+				continue
+			}
+			col := lipgloss.Width(line[:from+i])
+			if x >= col && x < col+lipgloss.Width(label) {
+				return a, true
+			}
+			from += i + len(label)
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/chart/studies"
+)
+
+// defaultStudies are listed in the studies pane until the user picks
+// their own.
+var defaultStudies = []studies.Spec{
+	{Kind: studies.KindSMA, Period: 20},
+	{Kind: studies.KindEMA, Period: 50},
+	{Kind: studies.KindBollinger, Period: 20},
+}
+
+// logLimit is how many lines the log pane keeps.
+const logLimit = 200
+
+// logBuffer holds the newest log lines for the log pane. The model
+// keeps a pointer so every copy of it appends to the same buffer; the
+// logger writes to it from fetches in flight too, hence the lock.
+type logBuffer struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (b *logBuffer) add(t time.Time, line string) {
+	if b == nil || line == "" {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lines = append(b.lines, t.Format("15:04:05")+" "+line)
+	if len(b.lines) > logLimit {
+		b.lines = append(b.lines[:0], b.lines[len(b.lines)-logLimit:]...)
+	}
+}
+
+// recent returns a copy of the lines, oldest first.
+func (b *logBuffer) recent() []string {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return slices.Clone(b.lines)
+}
+
+// barAt is the crosshair bar, or the last one when the crosshair is off.
+func (m model) barAt() int {
+	if c := m.vp.cursor; c >= 0 && c < len(m.ticks) {
+		return c
+	}
+	return len(m.ticks) - 1
+}
+
+// quotePane sums up the charted symbol: last price and change, the last
+// session's range and volume, and the crosshair bar.
+func (m model) quotePane(w int) string {
+	lines := []string{paneTitle("Quote "+m.symbol, w)}
+	if len(m.ticks) == 0 {
+		return lines[0] + "\n" + subtle.Render("no data")
+	}
+	last := m.ticks[len(m.ticks)-1]
+	q, ok := m.wl.quotes[m.symbol]
+	if !ok {
+		q = chart.Quote{Last: last.C}
+		if _, _, c, ok := chart.PriorSession(m.ticks); ok {
+			q.PrevClose = c
+		}
+	}
+	lines = append(lines, fmt.Sprintf("Last  %10.2f", q.Last))
+	if q.PrevClose != 0 {
+		lines = append(lines, fmt.Sprintf("Chg   %+10.2f %+.2f%%", q.Last-q.PrevClose, q.Change()))
+		lines = append(lines, fmt.Sprintf("Prev  %10.2f", q.PrevClose))
+	}
+	// the last session: bars sharing the last bar's date
+	y, mo, d := last.T.Date()
+	open, hi, lo, vol := last.O, last.H, last.L, int64(0)
+	for i := len(m.ticks) - 1; i >= 0; i-- {
+		k := m.ticks[i]
+		if ky, km, kd := k.T.Date(); ky != y || km != mo || kd != d {
+			break
+		}
+		open, hi, lo, vol = k.O, math.Max(hi, k.H), math.Min(lo, k.L), vol+k.V
+	}
+	lines = append(lines,
+		fmt.Sprintf("Open  %10.2f", open),
+		fmt.Sprintf("High  %10.2f", hi),
+		fmt.Sprintf("Low   %10.2f", lo),
+		fmt.Sprintf("Vol   %10d", vol))
+	if c := m.vp.cursor; c >= 0 && c < len(m.ticks) {
+		k := m.ticks[c]
+		lines = append(lines, "", subtle.Render("▸ "+k.T.Format("01-02 15:04")),
+			fmt.Sprintf("O %.2f H %.2f", k.O, k.H), fmt.Sprintf("L %.2f C %.2f", k.L, k.C))
+	}
+	return joinLines(lines, w)
+}
+
+// studiesPane lists each study's value at the crosshair bar.
+func (m model) studiesPane(w int) string {
+	i := m.barAt()
+	title := "Studies"
+	if i >= 0 {
+		title += " @ " + m.ticks[i].T.Format("15:04")
+	}
+	lines := []string{paneTitle(title, w)}
+	if i < 0 {
+		return lines[0] + "\n" + subtle.Render("no data")
+	}
+	closes := make([]float64, len(m.ticks))
+	for j, k := range m.ticks {
+		closes[j] = k.C
+	}
+	for _, s := range m.studies {
+		for _, l := range s.Apply(closes) {
+			v := l.Values[i]
+			if math.IsNaN(v) {
+				lines = append(lines, fmt.Sprintf("%-14s %10s", l.Name, "—"))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%-14s %10.2f", l.Name, v))
+		}
+	}
+	return joinLines(lines, w)
+}
+
+// newsPane lists the symbol's events (annotations), newest last.
+func (m model) newsPane(w, h int) string {
+	var lines []string
+	for _, a := range m.notes {
+		lines = append(lines, truncate(a.Time.Format("01-02 15:04")+" "+string(a.Kind)+" "+a.String(), w))
+	}
+	if len(lines) == 0 {
+		lines = []string{subtle.Render("no events for " + m.symbol)}
+	}
+	return fitLines(paneTitle("News & events", w), lines, h)
+}
+
+// logPane shows the newest log lines.
+func (m model) logPane(w, h int) string {
+	var lines []string
+	for _, l := range m.log.recent() {
+		lines = append(lines, truncate(l, w))
+	}
+	return fitLines(paneTitle("Log", w), lines, h)
+}
+
+func joinLines(lines []string, w int) string {
+	out := lines[0]
+	for _, l := range lines[1:] {
+		out += "\n" + truncate(l, w)
+	}
+	return out
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"ticker-forge/internal/chart"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// patternPanelWidth is the width of the side panel listing candlestick
+// patterns, border included.
+const patternPanelWidth = 32
+
+var panelStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.NormalBorder()).
+	BorderLeft(true).
+	PaddingLeft(1).
+	Width(patternPanelWidth - 1)
+
+// withPatternPanel draws ch without its header, caption and footer, puts
+// the pattern panel to the right of the plot and adds them back around
+// the pair.
+func (m model) withPatternPanel(ch chart.ASCIIChart, visible []chart.Tick, start int) string {
+	header, caption, footer := ch.Header, ch.Caption, ch.Footer
+	ch.Header, ch.Caption, ch.Footer = "", "", ""
+	var plot string
+	if m.renderer != nil {
+		plot = m.renderer.Render(ch, visible)
+	} else {
+		plot = ch.Render(visible)
+	}
+	plot = strings.TrimSuffix(strings.TrimPrefix(plot, "\n\n"), "\n\n")
+	panel := m.patternPanel(strings.Count(plot, "\n")+1, start, start+len(visible))
+	return header + "\n" + caption + "\n" + lipgloss.JoinHorizontal(lipgloss.Top, plot, panel) + "\n" + footer + "\n"
+}
+
+// patternPanel lists the patterns found, newest first, in at most rows
+// lines; those completing on the crosshair bar are highlighted and those
+// outside the visible bars [start, end) dimmed.
+func (m model) patternPanel(rows, start, end int) string {
+	lines := []string{titleStyle.Render(fmt.Sprintf("Patterns (%d)", len(m.found)))}
+	if len(m.found) == 0 {
+		lines = append(lines, subtle.Render("none found"))
+	}
+	for i := len(m.found) - 1; i >= 0 && len(lines) < rows; i-- {
+		p := m.found[i]
+		line := fmt.Sprintf("%s %s %s", p.Time.Format("01-02 15:04"), patternGlyph(p.Pattern), p.Pattern)
+		if r := []rune(line); len(r) > patternPanelWidth-2 {
+			line = string(r[:patternPanelWidth-3]) + "…"
+		}
+		switch {
+		case p.Bar == m.vp.cursor:
+			line = accentStyle.Render(line)
+		case p.Bar < start || p.Bar >= end:
+			line = subtle.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	return panelStyle.Height(rows).MaxHeight(rows).Render(strings.Join(lines, "\n"))
+}
+
+// patternGlyph matches the chart's marker for p.
+func patternGlyph(p chart.Pattern) string {
+	switch p.Bias() {
+	case 1:
+		return "△"
+	case -1:
+		return "▽"
+	}
+	return "◇"
+}
@@ -0,0 +1,161 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pickerIntervals are the picker's columns: cmdIntervals without the 1h
+// alias of 60m.
+var pickerIntervals = slices.DeleteFunc(slices.Clone(cmdIntervals), func(s string) bool { return s == "1h" })
+
+// rangeDays is how far back each range reaches, in days.
+var rangeDays = map[string]float64{
+	"1d": 1, "5d": 5, "1mo": 31, "3mo": 92, "6mo": 183, "1y": 366, "2y": 730,
+	"5y": 1827, "10y": 3653, "ytd": 366, "max": math.Inf(1),
+}
+
+// intervalDays is each bar size in days and how far back Yahoo serves
+// bars of that size.
+var intervalDays = map[string]struct{ bar, history float64 }{
+	"1m": {1.0 / 1440, 7}, "2m": {2.0 / 1440, 60}, "5m": {5.0 / 1440, 60},
+	"15m": {15.0 / 1440, 60}, "30m": {30.0 / 1440, 60}, "60m": {1.0 / 24, 730},
+	"1h": {1.0 / 24, 730}, "90m": {1.5 / 24, 60},
+	"1d": {1, math.Inf(1)}, "5d": {5, math.Inf(1)}, "1wk": {7, math.Inf(1)},
+	"1mo": {30, math.Inf(1)}, "3mo": {91, math.Inf(1)},
+}
+
+// supported reports whether Yahoo charts rng in bars of interval: the
+// bars must reach back that far and more than one must fit.
+func supported(rng, interval string) bool {
+	span, ok := rangeDays[rng]
+	iv, ok2 := intervalDays[interval]
+	return ok && ok2 && span <= iv.history && 2*iv.bar <= span
+}
+
+// fitPair keeps rng and interval when Yahoo serves them together. If not,
+// it changes the one not just chosen (keep names it: "range" or
+// "interval") to the nearest that works, and says so in note.
+func fitPair(rng, interval, keep string) (r, iv, note string) {
+	if supported(rng, interval) {
+		return rng, interval, ""
+	}
+	if keep == "range" {
+		// the finest bars that work
+		for _, iv := range pickerIntervals {
+			if supported(rng, iv) {
+				return rng, iv, fmt.Sprintf("no %s bars over %s; using %s", interval, rng, iv)
+			}
+		}
+		return rng, interval, ""
+	}
+	// the longest range the bars reach
+	for i := len(ranges) - 1; i >= 0; i-- {
+		if r := ranges[i]; r != "ytd" && supported(r, interval) && rangeDays[r] <= rangeDays[rng] {
+			return r, interval, fmt.Sprintf("no %s bars over %s; using %s", interval, rng, r)
+		}
+	}
+	for _, r := range ranges {
+		if supported(r, interval) {
+			return r, interval, fmt.Sprintf("no %s bars over %s; using %s", interval, rng, r)
+		}
+	}
+	return rng, interval, ""
+}
+
+// rangePicker is the state of the range/interval overlay: whether it is
+// open and the cell selected, a row of ranges by a column of
+// pickerIntervals.
+type rangePicker struct {
+	open     bool
+	row, col int
+}
+
+// openPicker opens the picker on the active tab's range and interval.
+func (m model) openPicker() model {
+	m.picker = rangePicker{open: true, row: max(slices.Index(ranges, m.rng), 0), col: max(slices.Index(pickerIntervals, m.interval), 0)}
+	if m.interval == "1h" {
+		m.picker.col = slices.Index(pickerIntervals, "60m")
+	}
+	return m
+}
+
+// pickerKey moves the picker's selection with the arrows or h/j/k/l,
+// charts it on enter and closes on esc or q.
+func (m model) pickerKey(key string) (model, tea.Cmd) {
+	p := &m.picker
+	switch key {
+	case "up", "k":
+		p.row = max(p.row-1, 0)
+	case "down", "j":
+		p.row = min(p.row+1, len(ranges)-1)
+	case "left", "h":
+		p.col = max(p.col-1, 0)
+	case "right", "l":
+		p.col = min(p.col+1, len(pickerIntervals)-1)
+	case "enter":
+		rng, interval := ranges[p.row], pickerIntervals[p.col]
+		if !supported(rng, interval) {
+			m.status = fmt.Sprintf("no %s bars over %s", interval, rng)
+			return m, nil
+		}
+		p.open, m.status = false, ""
+		if rng == m.rng && interval == m.interval {
+			return m, nil
+		}
+		m.rng, m.interval = rng, interval
+		m.vp = newViewport()
+		m.loading = true
+		return m, m.fetch()
+	case "esc", "q":
+		p.open = false
+	}
+	return m, nil
+}
+
+// pickerView draws the picker centred in w×h: ranges down, intervals
+// across, unsupported pairs greyed, the active tab's pair in bold and
+// the selection highlighted.
+func (m model) pickerView(w, h int) string {
+	const cell = 5
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Range / interval") + "\n\n")
+	b.WriteString(strings.Repeat(" ", cell))
+	for _, iv := range pickerIntervals {
+		b.WriteString(subtle.Render(fmt.Sprintf("%*s", cell, iv)))
+	}
+	b.WriteString("\n")
+	greyed := subtle.Faint(true)
+	for r, rng := range ranges {
+		b.WriteString(subtle.Render(fmt.Sprintf("%-*s", cell, rng)))
+		for c, iv := range pickerIntervals {
+			mark := "·"
+			style := greyed
+			if supported(rng, iv) {
+				mark, style = "●", lipgloss.NewStyle()
+			}
+			if rng == m.rng && (iv == m.interval || m.interval == "1h" && iv == "60m") {
+				mark, style = "◉", style.Bold(true)
+			}
+			if r == m.picker.row && c == m.picker.col {
+				style = style.Reverse(true)
+			}
+			b.WriteString(strings.Repeat(" ", cell-2) + style.Render(" "+mark))
+		}
+		b.WriteString("\n")
+	}
+	rng, iv := ranges[m.picker.row], pickerIntervals[m.picker.col]
+	note := rng + " of " + iv + " bars"
+	if !supported(rng, iv) {
+		note = errStyle.Render(note + ": not served")
+	}
+	b.WriteString("\n" + note + "\n" +
+		hintStyle.Render("←↓↑→/hjkl move • enter charts • esc closes • ◉ charted now"))
+	box := paneFocused.Padding(0, 1).Render(b.String())
+	return lipgloss.Place(w, h, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,52 @@
+package cli
+
+import "testing"
+
+func TestSupported(t *testing.T) {
+	cases := []struct {
+		rng, interval string
+		want          bool
+	}{
+		{"1d", "1m", true},
+		{"5d", "1m", true},
+		{"1mo", "1m", false}, // 1m bars only reach back a week
+		{"1mo", "5m", true},
+		{"3mo", "5m", false},
+		{"1y", "60m", true},
+		{"1y", "1h", true},
+		{"1d", "1d", false}, // a single bar
+		{"5d", "1d", true},
+		{"max", "1mo", true},
+		{"max", "1m", false},
+		{"1d", "7m", false},
+		{"2d", "1m", false},
+	}
+	for _, c := range cases {
+		if got := supported(c.rng, c.interval); got != c.want {
+			t.Errorf("supported(%s, %s) = %v, want %v", c.rng, c.interval, got, c.want)
+		}
+	}
+}
+
+func TestFitPair(t *testing.T) {
+	cases := []struct {
+		rng, interval, keep string
+		wantRange, wantIv   string
+		note                string
+	}{
+		{"5d", "15m", "range", "5d", "15m", ""},
+		{"1y", "1m", "range", "1y", "60m", "no 1m bars over 1y; using 60m"},
+		{"1d", "1d", "range", "1d", "1m", "no 1d bars over 1d; using 1m"},
+		{"1y", "1m", "interval", "5d", "1m", "no 1m bars over 1y; using 5d"},
+		{"max", "5m", "interval", "1mo", "5m", "no 5m bars over max; using 1mo"},
+		// no shorter range fits weekly bars: the shortest that does
+		{"1d", "1wk", "interval", "1mo", "1wk", "no 1wk bars over 1d; using 1mo"},
+	}
+	for _, c := range cases {
+		r, iv, note := fitPair(c.rng, c.interval, c.keep)
+		if r != c.wantRange || iv != c.wantIv || note != c.note {
+			t.Errorf("fitPair(%s, %s, %s) = %s, %s, %q; want %s, %s, %q",
+				c.rng, c.interval, c.keep, r, iv, note, c.wantRange, c.wantIv, c.note)
+		}
+	}
+}
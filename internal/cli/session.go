@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/chart/studies"
+	"ticker-forge/internal/session"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// session captures what restore brings back on the next start.
+func (m model) session() session.Session {
+	s := session.Session{Active: m.tab, Grid: m.grid, Focus: m.focus, History: m.history, Studies: []string{}}
+	for i := range m.tabs {
+		t := m.tabState(i)
+		syms := []string{t.symbol}
+		if len(t.compare) > 1 {
+			syms = t.compare
+		}
+		tab := session.Tab{
+			Symbols: syms, Range: t.rng, Interval: t.interval,
+			View: t.view.String(), Scale: t.scale.String(), Profile: t.profile.String(), Pivots: t.pivots.String(),
+			Zones: t.zones, Patterns: t.patterns, Volume: t.volume,
+			Span: t.vp.span, Offset: t.vp.offset, Cursor: t.vp.cursor,
+		}
+		if t.refreshEvery > 0 {
+			tab.Refresh = t.refreshEvery.String()
+		}
+		s.Tabs = append(s.Tabs, tab)
+	}
+	for _, spec := range m.studies {
+		s.Studies = append(s.Studies, spec.String())
+	}
+	return s
+}
+
+// restore reopens the tabs of s in place of the one the model starts
+// with. Values it can't read fall back to that tab's.
+func (m model) restore(s *session.Session) model {
+	var tabs []chartState
+	for _, t := range s.Tabs {
+		syms := chart.ParseSymbols(strings.Join(t.Symbols, ","))
+		if len(syms) == 0 {
+			continue
+		}
+		c := m.chartState
+		m.nextID++
+		c.id, c.symbol, c.compare = m.nextID, syms[0], nil
+		if len(syms) > 1 {
+			c.compare = syms
+		}
+		if slices.Contains(ranges, t.Range) {
+			c.rng = t.Range
+		}
+		if slices.Contains(cmdIntervals, t.Interval) {
+			c.interval = t.Interval
+		}
+		if v, ok := chart.ParseViewMode(t.View); ok {
+			c.view = v
+		}
+		if sc, ok := chart.ParseScale(t.Scale); ok {
+			c.scale = sc
+		}
+		if p, ok := chart.ParseProfileMode(t.Profile); ok {
+			c.profile = p
+		}
+		if p, ok := chart.ParsePivotMethod(t.Pivots); ok {
+			c.pivots = p
+		}
+		c.zones, c.patterns, c.volume = t.Zones, t.Patterns, t.Volume
+		c.refreshEvery = 0
+		if d, err := time.ParseDuration(t.Refresh); err == nil && d > 0 {
+			c.refreshEvery = d
+		}
+		c.vp = viewport{span: max(t.Span, 0), offset: max(t.Offset, 0), cursor: max(t.Cursor, -1)}
+		tabs = append(tabs, c)
+	}
+	if len(tabs) == 0 {
+		return m
+	}
+	m.tabs = tabs
+	m.tab = min(max(s.Active, 0), len(tabs)-1)
+	m.chartState = tabs[m.tab]
+	m.grid = s.Grid
+	if m.shows(s.Focus) {
+		m.focus = s.Focus
+	}
+	if s.Studies != nil {
+		specs, err := studies.ParseSpecs(strings.Join(s.Studies, ","))
+		if err == nil {
+			m.studies = specs
+		}
+	}
+	m.history = s.History
+	return m
+}
+
+// withFlags charts the -symbol, -range and -interval given on the command
+// line in the active tab, over what a restored session put there.
+func (m model) withFlags(opts Options) model {
+	set := opts.SetFlags
+	if !set["symbol"] && !set["range"] && !set["interval"] {
+		return m
+	}
+	if set["symbol"] {
+		if syms := chart.ParseSymbols(opts.DefaultSymbol); len(syms) > 0 {
+			m.symbol, m.compare = syms[0], nil
+			if len(syms) > 1 {
+				m.compare = syms
+			}
+		}
+	}
+	if set["range"] {
+		m.rng = opts.DefaultRange
+	}
+	if set["interval"] {
+		m.interval = opts.DefaultInterval
+	}
+	m.vp = newViewport()
+	m.tabs[m.tab] = m.chartState
+	return m
+}
+
+// saveSession stores the session of the TUI's final model.
+func saveSession(final tea.Model) error {
+	m, ok := final.(model)
+	if !ok {
+		return nil
+	}
+	return m.session().Save("")
+}
+
+// fetchAll fetches every tab, each starting its refresh schedule.
+func (m model) fetchAll() tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(m.tabs))
+	for i := range m.tabs {
+		s := m.tabState(i)
+		_, cmd := m.onTab(s.id, func(m model) (model, tea.Cmd) { return m, m.fetchTab(true) })
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
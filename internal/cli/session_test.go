@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/session"
+)
+
+// sessionModel is a fresh model with two tabs, the second active.
+func sessionModel(t *testing.T) model {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(Options{})
+	a := m.chartState
+	a.symbol, a.rng, a.interval = "MSFT", "5d", "15m"
+	a.view, a.scale, a.pivots, a.zones = chart.ViewLine, chart.ScaleLog, chart.PivotFibonacci, true
+	a.vp = viewport{span: 40, offset: 12, cursor: 7}
+	b := m.chartState
+	b.symbol, b.compare, b.rng, b.interval = "AAPL", []string{"AAPL", "GOOG"}, "1mo", "1h"
+	b.profile, b.patterns, b.volume, b.refreshEvery = chart.ProfileTPO, true, true, 30*time.Second
+	b.vp = viewport{cursor: -1}
+	m.tabs, m.tab, m.chartState = []chartState{a, b}, 1, b
+	m.grid, m.focus = true, paneWatchlist
+	m.history = []string{"range 5d", "grid"}
+	m.studies = nil
+	return m
+}
+
+func TestSessionRoundTrip(t *testing.T) {
+	m := sessionModel(t)
+	want := m.session()
+	path := filepath.Join(t.TempDir(), "session.yaml")
+	if err := want.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	s, err := session.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*s, want) {
+		t.Fatalf("loaded %+v\nsaved %+v", *s, want)
+	}
+
+	restored := initialModel(Options{}).restore(s)
+	if got := restored.session(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored session %+v\nwant %+v", got, want)
+	}
+	if restored.tab != 1 || restored.symbol != "AAPL" || len(restored.studies) != 0 {
+		t.Errorf("active tab %d charting %s with %d studies", restored.tab, restored.symbol, len(restored.studies))
+	}
+}
+
+func TestRestoreSkipsBadValues(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(Options{})
+	s := &session.Session{Active: 5, Focus: "nowhere", Tabs: []session.Tab{
+		{Symbols: nil, Range: "5d"}, // no symbol: dropped
+		{Symbols: []string{"ibm"}, Range: "2d", Interval: "7m", View: "pie", Refresh: "soon", Cursor: -9},
+	}}
+	got := m.restore(s)
+	if len(got.tabs) != 1 || got.tab != 0 {
+		t.Fatalf("restored %d tabs, active %d", len(got.tabs), got.tab)
+	}
+	c := got.chartState
+	if c.symbol != "IBM" || c.rng != m.rng || c.interval != m.interval || c.view != m.view ||
+		c.refreshEvery != 0 || c.vp.cursor != -1 || got.focus != m.focus {
+		t.Errorf("restored %+v, focus %q", c, got.focus)
+	}
+	if got := m.restore(&session.Session{}); !reflect.DeepEqual(got.session(), m.session()) {
+		t.Error("an empty session changed the model")
+	}
+}
+
+func TestWithFlags(t *testing.T) {
+	cases := []struct {
+		name                  string
+		set                   []string
+		symbolFlag            string
+		symbol, rng, interval string
+		compare               []string
+	}{
+		{"none given", nil, "tsla", "AAPL", "1mo", "1h", []string{"AAPL", "GOOG"}},
+		{"other flags", []string{"theme", "fresh"}, "tsla", "AAPL", "1mo", "1h", []string{"AAPL", "GOOG"}},
+		{"symbol", []string{"symbol"}, "tsla", "TSLA", "1mo", "1h", nil},
+		{"symbols to compare", []string{"symbol"}, "nvda,amd", "NVDA", "1mo", "1h", []string{"NVDA", "AMD"}},
+		{"range and interval", []string{"range", "interval"}, "tsla", "AAPL", "1y", "1d", []string{"AAPL", "GOOG"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			m := sessionModel(t)
+			opts := Options{DefaultSymbol: c.symbolFlag, DefaultRange: "1y", DefaultInterval: "1d", SetFlags: map[string]bool{}}
+			for _, f := range c.set {
+				opts.SetFlags[f] = true
+			}
+			got := m.withFlags(opts)
+			if got.symbol != c.symbol || got.rng != c.rng || got.interval != c.interval ||
+				!reflect.DeepEqual(got.compare, c.compare) {
+				t.Errorf("got %s %v %s/%s, want %s %v %s/%s", got.symbol, got.compare, got.rng, got.interval,
+					c.symbol, c.compare, c.rng, c.interval)
+			}
+			if other := got.tabs[0]; other.symbol != "MSFT" || other.rng != "5d" {
+				t.Errorf("flags changed the other tab: %s %s", other.symbol, other.rng)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/theme"
+
+	"github.com/muesli/termenv"
+)
+
+// SnapshotOptions configures the snapshot command.
+type SnapshotOptions struct {
+	Symbol   string
+	Range    string
+	Interval string
+	View     string // line or candles
+	Width    int
+	Height   int
+	Theme    string
+	// Color is auto (as stdout supports, honoring NO_COLOR and
+	// CLICOLOR_FORCE), always (24-bit), 256 or never.
+	Color string
+}
+
+// snapshotColors maps SnapshotOptions.Color to a color profile.
+var snapshotColors = map[string]termenv.Profile{
+	"always": termenv.TrueColor,
+	"256":    termenv.ANSI256,
+	"never":  termenv.Ascii,
+}
+
+// Snapshot fetches one symbol and prints its line or candle chart as text
+// to stdout, for cron jobs and CI logs. A failed fetch is an error.
+func Snapshot(opts SnapshotOptions) error {
+	symbol := strings.ToUpper(orDefault(opts.Symbol, "AAPL"))
+	rng, interval := orDefault(opts.Range, "1d"), orDefault(opts.Interval, "1m")
+	view, ok := chart.ParseViewMode(orDefault(opts.View, "candles"))
+	if !ok || view != chart.ViewLine && view != chart.ViewCandles {
+		return fmt.Errorf("snapshot: unknown view %q; use line or candles", opts.View)
+	}
+	ch := chart.ASCIIChart{View: view, Width: opts.Width, Height: opts.Height, Cursor: -1}
+	if w, h := ch.MinSize(); opts.Width < w || opts.Height < h {
+		return fmt.Errorf("snapshot: the %s view needs at least %d×%d, not %d×%d", view, w, h, opts.Width, opts.Height)
+	}
+	profile, ok := snapshotColors[opts.Color]
+	switch {
+	case opts.Color == "" || opts.Color == "auto":
+		profile = theme.Detect()
+	case !ok:
+		return fmt.Errorf("snapshot: unknown color %q; use auto, always, 256 or never", opts.Color)
+	}
+	t, err := loadTheme(opts.Theme)
+	if err != nil {
+		return err
+	}
+	pal := t.Palette(profile)
+	ch.Palette = &pal
+
+	ticks, err := chart.FetchIntradayOHLC(symbol, rng, interval)
+	if err != nil {
+		return fmt.Errorf("snapshot: %s: %w", symbol, err)
+	}
+	if len(ticks) < 2 {
+		return fmt.Errorf("snapshot: %s: no data for %s/%s", symbol, rng, interval)
+	}
+
+	last := ticks[len(ticks)-1]
+	ch.Caption = fmt.Sprintf("%s  %s/%s  %s   last: %.2f   as of: %s",
+		symbol, rng, interval, view, last.C, last.T.Format("2006-01-02 15:04"))
+	// no header or footer: the caption opens the output and the plot ends it
+	out := strings.TrimPrefix(ch.Render(ticks), "\n")
+	_, err = io.WriteString(os.Stdout, strings.TrimRight(out, "\n")+"\n")
+	return err
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"ticker-forge/internal/chart"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// intervals are the bar sizes i and I step through.
+var intervals = []string{"1m", "2m", "5m", "15m", "30m", "60m", "90m", "1d"}
+
+// refreshSteps are the refresh schedules R steps through; 0 is off.
+var refreshSteps = []time.Duration{0, 15 * time.Second, 30 * time.Second, time.Minute, 5 * time.Minute}
+
+// tabState returns tab i's state, the live one for the active tab.
+func (m model) tabState(i int) chartState {
+	if i == m.tab {
+		return m.chartState
+	}
+	return m.tabs[i]
+}
+
+// onTab runs f with the tab whose id is id made active, then makes the
+// active tab active again. Messages for a closed tab are dropped.
+func (m model) onTab(id int, f func(model) (model, tea.Cmd)) (model, tea.Cmd) {
+	if id == m.id {
+		return f(m)
+	}
+	i := slices.IndexFunc(m.tabs, func(s chartState) bool { return s.id == id })
+	if i < 0 || i == m.tab {
+		return m, nil
+	}
+	active := m.chartState
+	m.chartState = m.tabs[i]
+	m, cmd := f(m)
+	m.tabs = slices.Clone(m.tabs)
+	m.tabs[i], m.chartState = m.chartState, active
+	return m, cmd
+}
+
+// switchTab makes tab i active.
+func (m model) switchTab(i int) model {
+	if i < 0 || i >= len(m.tabs) || i == m.tab {
+		return m
+	}
+	m.tabs = slices.Clone(m.tabs)
+	m.tabs[m.tab] = m.chartState
+	m.tab, m.chartState = i, m.tabs[i]
+	m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	return m
+}
+
+// newTab opens a tab charting what the active one does, after it, and
+// fetches it.
+func (m model) newTab() (model, tea.Cmd) {
+	s := m.chartState
+	m.nextID++
+	s.id, s.loading, s.pending = m.nextID, true, nil
+	m.tabs = slices.Insert(slices.Clone(m.tabs), m.tab+1, s)
+	m = m.switchTab(m.tab + 1)
+	m.status = fmt.Sprintf("tab %d of %d", m.tab+1, len(m.tabs))
+	return m, m.fetchTab(true)
+}
+
+// closeTab closes the active tab and activates the one before it.
+func (m model) closeTab() model {
+	if len(m.tabs) == 1 {
+		m.status = "this is the last tab"
+		return m
+	}
+	m.tabs = slices.Delete(slices.Clone(m.tabs), m.tab, m.tab+1)
+	m.tab = max(m.tab-1, 0)
+	m.chartState = m.tabs[m.tab]
+	m.vp.clamp(len(m.ticks), m.chart().Capacity())
+	return m
+}
+
+// stepInterval moves the active tab to the interval delta places along
+// intervals and fetches it.
+func (m model) stepInterval(delta int) (model, tea.Cmd) {
+	i := slices.Index(intervals, m.interval)
+	if i < 0 {
+		i = 0
+	} else {
+		i = (i + delta + len(intervals)) % len(intervals)
+	}
+	m.rng, m.interval, m.status = fitPair(m.rng, intervals[i], "interval")
+	m.vp = newViewport()
+	m.loading = true
+	return m, m.fetch()
+}
+
+// stepRefresh moves the active tab to the next refresh schedule and
+// starts it.
+func (m model) stepRefresh() (model, tea.Cmd) {
+	i := slices.Index(refreshSteps, m.refreshEvery)
+	m.refreshEvery = refreshSteps[(i+1)%len(refreshSteps)]
+	m.refreshGen++
+	m.status = "refresh off"
+	if m.refreshEvery > 0 {
+		m.status = "refresh every " + m.refreshEvery.String()
+	}
+	return m, tickCmd(m.id, m.refreshGen, m.refreshEvery)
+}
+
+// tabBar lists the tabs after the title, the active one highlighted.
+func (m model) tabBar() string {
+	if len(m.tabs) < 2 {
+		return ""
+	}
+	parts := make([]string, len(m.tabs))
+	for i := range m.tabs {
+		s := m.tabState(i)
+		label := fmt.Sprintf(" %d %s %s/%s ", i+1, s.symbols(), s.rng, s.interval)
+		if i == m.tab {
+			label = accentStyle.Render("[" + strings.TrimSpace(label) + "]")
+		} else {
+			label = subtle.Render(label)
+		}
+		parts[i] = label
+	}
+	return "  " + strings.Join(parts, " ")
+}
+
+// gridSize is the content size of one of the grid's cells when the grid
+// fills w×h.
+func gridSize(w, h int) (int, int) {
+	return max(w/2-2, 1), max(h/2-2, 1)
+}
+
+// viewGrid draws four tabs' charts in a 2×2 grid filling w×h: the group
+// of four holding the active tab.
+func (m model) viewGrid(w, h int) string {
+	first := m.tab / 4 * 4
+	cw, ch := gridSize(w, h)
+	cells := make([]string, 4)
+	for k := range cells {
+		i := first + k
+		body := subtle.Render(m.keys.keys(actTabNew) + " opens a tab")
+		if i < len(m.tabs) {
+			body = m.gridCell(m.tabState(i), i, cw, ch)
+		}
+		body = lipgloss.NewStyle().MaxWidth(cw).MaxHeight(ch).Render(body)
+		style := paneBorder
+		if i == m.tab {
+			style = paneFocused
+		}
+		cells[k] = style.Width(cw).Height(ch).Render(body)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left,
+		lipgloss.JoinHorizontal(lipgloss.Top, cells[0], cells[1]),
+		lipgloss.JoinHorizontal(lipgloss.Top, cells[2], cells[3]))
+}
+
+// gridCell draws tab i (state s) in a w×h grid cell with the ASCII
+// renderers, titled by its number, symbol and last price.
+func (m model) gridCell(s chartState, i, w, h int) string {
+	title := fmt.Sprintf("%d %s  %s/%s  %s", i+1, s.symbols(), s.rng, s.interval, s.view)
+	switch {
+	case s.err != nil:
+		return title + "\n" + errStyle.Render("error: "+s.err.Error())
+	case len(s.ticks) < 2:
+		return title + "\n" + hintStyle.Render("loading…")
+	}
+	title += fmt.Sprintf("  last %.2f", s.ticks[len(s.ticks)-1].C)
+
+	c := m
+	c.chartState = s
+	ch := c.chartSized(w, h)
+	ch.Caption = titleStyle.Render(title)
+	start, end := s.vp.window(len(s.ticks), ch.Capacity())
+	if mw, mh := ch.MinSize(); w < mw || h < mh {
+		// the chart would be clipped, losing the newest bars
+		return m.compactCell(s, ch, title, start, end, w, h)
+	}
+	if cur := s.vp.cursor; cur >= start && cur < end {
+		ch.Cursor = cur - start
+	}
+	ch.Annotations, ch.Drawings = s.notes, s.drawings
+	r := m.renderer
+	if r == nil {
+		r = &chart.Renderer{}
+	}
+	var out string
+	if len(s.series) > 1 {
+		visible := make([]chart.Series, len(s.series))
+		for k, sr := range s.series {
+			visible[k] = chart.Series{Name: sr.Name, Ticks: sr.Ticks[start:end]}
+		}
+		out = r.RenderCompare(ch, visible)
+	} else {
+		out = r.Render(ch, s.ticks[start:end])
+	}
+	return strings.TrimPrefix(out, "\n")
+}
+
+// compactCell draws the bars start:end of tab state s as a sparkline per
+// symbol with its last price and change, for a w×h grid cell too small
+// for the chart ch.
+func (m model) compactCell(s chartState, ch chart.ASCIIChart, title string, start, end, w, h int) string {
+	series := s.series
+	if len(series) <= 1 {
+		series = []chart.Series{{Name: s.symbol, Ticks: s.ticks}}
+	}
+	lines := []string{titleStyle.Render(truncate(title, w))}
+	for _, sr := range series[:min(len(series), max(h-2, 1))] {
+		ticks := sr.Ticks[start:min(end, len(sr.Ticks))]
+		if len(ticks) == 0 {
+			continue
+		}
+		closes := make([]float64, len(ticks))
+		for k, t := range ticks {
+			closes[k] = t.C
+		}
+		first, last := closes[0], closes[len(closes)-1]
+		change := 0.0
+		if first != 0 {
+			change = (last/first - 1) * 100
+		}
+		// the sparkline gets whatever the 24 columns before it leave
+		spark := chart.Sparkline{Width: max(w-24, 1), Color: true, Palette: &m.palette}.Render(closes)
+		lines = append(lines, fmt.Sprintf("%-6s %8.2f %+6.2f%% %s", sr.Name, last, change, spark))
+	}
+	if len(lines) < h {
+		mw, mh := ch.MinSize()
+		lines = append(lines, hintStyle.Render(truncate(fmt.Sprintf("a chart needs %d×%d", mw, mh), w)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"ticker-forge/internal/chart"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestGridCells(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(Options{})
+	t0 := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+	for i := range 120 {
+		p := 100 + float64(i)/10
+		m.ticks = append(m.ticks, chart.Tick{T: t0.Add(time.Duration(i) * time.Minute), O: p, H: p + 0.2, L: p - 0.2, C: p, V: 100})
+	}
+	m.loading, m.grid = false, true
+	m.tabs[0] = m.chartState
+	last := fmt.Sprintf("%-6s %8.2f", "AAPL", m.ticks[len(m.ticks)-1].C)
+
+	cases := []struct {
+		name    string
+		w, h    int
+		compact bool
+	}{
+		{"100×30 is too small for charts", 100, 30, true},
+		{"200×60 fits them", 200, 60, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cw, ch := gridSize(c.w, c.h)
+			cell := m.gridCell(m.chartState, 0, cw, ch)
+			if n := strings.Count(cell, "\n") + 1; n > ch {
+				t.Errorf("cell has %d lines, room for %d", n, ch)
+			}
+			if got := lipgloss.Width(cell); got > cw {
+				t.Errorf("cell is %d wide, room for %d", got, cw)
+			}
+			if compact := strings.Contains(cell, last); compact != c.compact {
+				t.Errorf("compact %v, want %v:\n%s", compact, c.compact, cell)
+			}
+			if grid := m.viewGrid(c.w, c.h); c.compact && !strings.Contains(grid, last) {
+				t.Errorf("the grid lost the last bar:\n%s", grid)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+// viewport is the slice of history the chart shows. It is anchored to the
+// newest bar so that auto-refresh appends bars without moving the view.
+type viewport struct {
+	span   int // bars on screen; 0 = as many as fit
+	offset int // bars scrolled off to the right; 0 = pinned to the newest bar
+	cursor int // absolute bar index under the crosshair; -1 = hidden
+}
+
+func newViewport() viewport {
+	return viewport{cursor: -1}
+}
+
+// window returns the [start, end) range of n bars to draw when at most
+// capacity fit on screen.
+func (v viewport) window(n, capacity int) (int, int) {
+	span := v.visible(n, capacity)
+	end := n - v.offset
+	if end > n {
+		end = n
+	}
+	if end < span {
+		end = span
+	}
+	return end - span, end
+}
+
+func (v viewport) visible(n, capacity int) int {
+	span := v.span
+	if span <= 0 || span > capacity {
+		span = capacity
+	}
+	if span > n {
+		span = n
+	}
+	return span
+}
+
+// zoom narrows (in) or widens (out) the window by a factor of two,
+// keeping its right edge in place.
+func (v *viewport) zoom(in bool, n, capacity int) {
+	span := v.visible(n, capacity)
+	if in {
+		span = max(minSpan, span/2)
+	} else {
+		span *= 2
+	}
+	if span >= capacity || span >= n {
+		span = 0
+	}
+	v.span = span
+	v.clamp(n, capacity)
+}
+
+// minSpan is the narrowest zoom level, in bars.
+const minSpan = 8
+
+// pan scrolls the window by delta bars (positive = back in time).
+func (v *viewport) pan(delta, n, capacity int) {
+	v.offset += delta
+	v.clamp(n, capacity)
+	start, end := v.window(n, capacity)
+	if v.cursor >= 0 {
+		v.cursor = min(max(v.cursor, start), end-1)
+	}
+}
+
+// move shifts the crosshair by delta bars, showing it first if hidden and
+// scrolling the window when it reaches an edge.
+func (v *viewport) move(delta, n, capacity int) {
+	if n == 0 {
+		return
+	}
+	start, end := v.window(n, capacity)
+	if v.cursor < 0 {
+		v.cursor = end - 1
+		return
+	}
+	v.cursor = min(max(v.cursor+delta, 0), n-1)
+	if v.cursor < start {
+		v.offset += start - v.cursor
+	} else if v.cursor >= end {
+		v.offset -= v.cursor - end + 1
+	}
+	v.clamp(n, capacity)
+}
+
+// clamp keeps offset and cursor valid after n or capacity changed.
+func (v *viewport) clamp(n, capacity int) {
+	span := v.visible(n, capacity)
+	v.offset = min(max(v.offset, 0), max(n-span, 0))
+	if v.cursor >= n {
+		v.cursor = n - 1
+	}
+}
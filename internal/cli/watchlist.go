@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/watchlist"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// quoteRefresh is how often the watchlist's quotes are fetched again.
+const quoteRefresh = 30 * time.Second
+
+// watchPane is the state of the watchlist pane: the stored lists, the
+// selected row of the shown one and the latest quote of each symbol.
+type watchPane struct {
+	store  watchlist.Store
+	sel    int
+	quotes map[string]chart.Quote
+	err    error // of the last quote fetch, shown under the list
+}
+
+type quotesMsg struct {
+	quotes   map[string]chart.Quote
+	err      error
+	periodic bool // fetched by the background refresh, which goes on
+}
+
+type quotesTickMsg struct{}
+
+type watchlistSavedMsg struct{ err error }
+
+// quotesCmd fetches the quotes of symbols.
+func quotesCmd(symbols []string, periodic bool) tea.Cmd {
+	if len(symbols) == 0 && !periodic {
+		return nil
+	}
+	symbols = append([]string(nil), symbols...)
+	return func() tea.Msg {
+		q, err := chart.FetchQuotes(symbols)
+		return quotesMsg{quotes: q, err: err, periodic: periodic}
+	}
+}
+
+func quotesTick() tea.Cmd {
+	return tea.Tick(quoteRefresh, func(time.Time) tea.Msg { return quotesTickMsg{} })
+}
+
+// saveWatchlistsCmd stores a copy of s, taken now.
+func saveWatchlistsCmd(s watchlist.Store) tea.Cmd {
+	s = s.Clone()
+	return func() tea.Msg { return watchlistSavedMsg{s.Save("")} }
+}
+
+// showWatchlist reports whether the watchlist pane is on screen.
+func (m model) showWatchlist() bool {
+	return m.usePanes() && m.shows(paneWatchlist)
+}
+
+// watchKey handles the watchlist actions: select a row, chart its symbol,
+// move or remove it, switch lists and delete an empty one. It reports
+// false for any other action.
+func (m model) watchKey(a action) (model, tea.Cmd, bool) {
+	l := m.wl.store.Active()
+	switch a {
+	case actWatchDown:
+		m.wl.sel = max(min(m.wl.sel+1, len(l.Symbols)-1), 0)
+	case actWatchUp:
+		m.wl.sel = max(m.wl.sel-1, 0)
+	case actWatchOpen:
+		if m.wl.sel >= 0 && m.wl.sel < len(l.Symbols) {
+			m, cmd := m.load([]string{l.Symbols[m.wl.sel]})
+			return m, cmd, true
+		}
+	case actWatchMoveDown, actWatchMoveUp:
+		delta := 1
+		if a == actWatchMoveUp {
+			delta = -1
+		}
+		if i := l.Move(m.wl.sel, delta); i != m.wl.sel {
+			m.wl.sel = i
+			return m, saveWatchlistsCmd(m.wl.store), true
+		}
+	case actWatchRemove:
+		if m.wl.sel >= 0 && m.wl.sel < len(l.Symbols) {
+			m.status = "removed " + l.Symbols[m.wl.sel] + " from " + l.Name
+			l.Remove(m.wl.sel)
+			m.wl.sel = min(m.wl.sel, max(len(l.Symbols)-1, 0))
+			return m, saveWatchlistsCmd(m.wl.store), true
+		}
+	case actWatchPrevList, actWatchNextList:
+		delta := 1
+		if a == actWatchPrevList {
+			delta = -1
+		}
+		l = m.wl.store.Switch(delta)
+		m.wl.sel = 0
+		return m, tea.Batch(saveWatchlistsCmd(m.wl.store), quotesCmd(m.missingQuotes(), false)), true
+	case actWatchDelList:
+		if len(l.Symbols) > 0 {
+			m.status = "remove the symbols of " + l.Name + " first (" + m.keys.keys(actWatchRemove) + ")"
+			return m, nil, true
+		}
+		if err := m.wl.store.Delete(); err != nil {
+			m.status = err.Error()
+			return m, nil, true
+		}
+		m.status = "deleted watchlist " + l.Name
+		m.wl.sel = 0
+		return m, tea.Batch(saveWatchlistsCmd(m.wl.store), quotesCmd(m.missingQuotes(), false)), true
+	default:
+		return m, nil, false
+	}
+	return m, nil, true
+}
+
+// watchInput applies the add symbols and new list prompts.
+func (m model) watchInput(kind inputKind, value string) (model, tea.Cmd) {
+	if kind == inputWatchNew {
+		l, err := m.wl.store.Create(value)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+		m.wl.sel = 0
+		m.status = "watchlist " + l.Name
+		return m, saveWatchlistsCmd(m.wl.store)
+	}
+	l := m.wl.store.Active()
+	syms := chart.ParseSymbols(value)
+	n := l.Add(syms...)
+	m.status = fmt.Sprintf("added %d to %s", n, l.Name)
+	if n == 0 {
+		return m, nil
+	}
+	m.wl.sel = len(l.Symbols) - 1
+	return m, tea.Batch(saveWatchlistsCmd(m.wl.store), quotesCmd(syms, false))
+}
+
+// missingQuotes lists the shown symbols with no quote yet.
+func (m model) missingQuotes() []string {
+	var out []string
+	for _, s := range m.wl.store.Active().Symbols {
+		if _, ok := m.wl.quotes[s]; !ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// watchlistPane draws the shown list in w×rows cells: symbol, last price,
+// change since the previous close and a sparkline of the session, with
+// the selected row marked and the charted symbol in bold.
+func (m model) watchlistPane(w, rows int) string {
+	l := m.wl.store.Active()
+	title := fmt.Sprintf("%s (%d)", l.Name, len(l.Symbols))
+	if n := len(m.wl.store.Lists); n > 1 {
+		title += subtle.Render(fmt.Sprintf("  %s %s %d lists", m.keys.keys(actWatchPrevList), m.keys.keys(actWatchNextList), n))
+	}
+	lines := []string{titleStyle.Render(title)}
+	if len(l.Symbols) == 0 {
+		lines = append(lines, subtle.Render("empty: "+m.keys.keys(actWatchAdd)+" adds symbols"))
+	}
+	// keep the selection in view
+	first := max(0, m.wl.sel-(rows-3))
+	for i := first; i < len(l.Symbols) && len(lines) < rows-1; i++ {
+		sym := l.Symbols[i]
+		mark := " "
+		if i == m.wl.sel {
+			mark = accentStyle.Render("▸")
+		}
+		name := fmt.Sprintf("%-6s", sym)
+		if sym == m.symbol && len(m.compare) <= 1 {
+			name = lipgloss.NewStyle().Bold(true).Render(name)
+		}
+		q, ok := m.wl.quotes[sym]
+		if !ok {
+			lines = append(lines, mark+name+subtle.Render("  …"))
+			continue
+		}
+		change := fmt.Sprintf("%+6.2f%%", q.Change())
+		if seq := m.palette.Down; q.Change() < 0 && seq != "" {
+			change = seq + change + m.palette.Reset
+		} else if seq := m.palette.Up; q.Change() >= 0 && seq != "" {
+			change = seq + change + m.palette.Reset
+		}
+		// the sparkline gets whatever the 25 columns before it leave
+		spark := chart.Sparkline{Width: max(w-25, 1), Color: true, Base: q.PrevClose, Palette: &m.palette}.Render(q.Closes)
+		lines = append(lines, fmt.Sprintf("%s%s %8.2f %s %s", mark, name, q.Last, change, spark))
+	}
+	if m.wl.err != nil {
+		lines = append(lines, errStyle.Render(truncate(m.wl.err.Error(), w)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate cuts s to n runes, ending it with … when cut.
+func truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
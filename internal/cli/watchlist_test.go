@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"testing"
+
+	"ticker-forge/internal/watchlist"
+)
+
+// An empty list has no row to select: moving down must not leave the
+// selection at -1 for open and remove to index with.
+func TestWatchKeyEmptyList(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	m := initialModel(Options{})
+	m.wl.store = watchlist.Store{Lists: []watchlist.List{{Name: "empty"}}}
+
+	for _, a := range []action{actWatchDown, actWatchOpen, actWatchRemove, actWatchMoveDown, actWatchUp, actWatchOpen} {
+		var ok bool
+		m, _, ok = m.watchKey(a)
+		if !ok {
+			t.Fatalf("%s: not handled", a)
+		}
+		if m.wl.sel != 0 {
+			t.Fatalf("%s: selection %d, want 0", a, m.wl.sel)
+		}
+	}
+}
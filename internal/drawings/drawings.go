@@ -0,0 +1,98 @@
+// Package drawings keeps the user's chart drawings (levels, trendlines,
+// Fibonacci retracements) per symbol in drawings.yaml next to
+// config.yaml.
+package drawings
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ticker-forge/internal/cfg"
+	"ticker-forge/internal/chart"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Store maps an upper-case symbol to its drawings, oldest first.
+type Store map[string][]chart.Drawing
+
+// DefaultPath returns the location of drawings.yaml.
+func DefaultPath() (string, error) {
+	dir, err := cfg.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "drawings.yaml"), nil
+}
+
+// Load reads the store at path (DefaultPath when empty); a missing file
+// yields an empty Store.
+func Load(path string) (Store, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	s := Store{}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes s to path (DefaultPath when empty), creating its directory.
+func (s Store) Save(path string) error {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return err
+		}
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// For returns symbol's drawings.
+func (s Store) For(symbol string) []chart.Drawing {
+	return s[strings.ToUpper(symbol)]
+}
+
+// Set replaces symbol's drawings; an empty list removes the symbol.
+func (s Store) Set(symbol string, ds []chart.Drawing) {
+	symbol = strings.ToUpper(symbol)
+	if len(ds) == 0 {
+		delete(s, symbol)
+		return
+	}
+	s[symbol] = ds
+}
+
+// Update loads the store at path, lets edit change symbol's drawings and
+// saves the result, returning the drawings edit left.
+func Update(path, symbol string, edit func([]chart.Drawing) []chart.Drawing) ([]chart.Drawing, error) {
+	s, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	ds := edit(s.For(symbol))
+	s.Set(symbol, ds)
+	return ds, s.Save(path)
+}
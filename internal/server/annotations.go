@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"ticker-forge/internal/annot"
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/drawings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// overlay collects what the chart pages draw over symbol's prices: its
+// annotations and drawings.
+func overlay(opts Options, symbol string) (chart.Overlay, error) {
+	notes, err := annot.Load(opts.Annotations)
+	if err != nil {
+		return chart.Overlay{}, err
+	}
+	store, err := drawings.Load("")
+	if err != nil {
+		return chart.Overlay{}, err
+	}
+	return chart.Overlay{Annotations: chart.AnnotationsFor(notes, symbol), Drawings: store.For(symbol)}, nil
+}
+
+// GET /api/annotations?symbol=AAPL  (all symbols when symbol is empty)
+func ListAnnotations(opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		notes, err := annot.Load(opts.Annotations)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if symbol := c.Query("symbol"); symbol != "" {
+			notes = chart.AnnotationsFor(notes, symbol)
+		}
+		if notes == nil {
+			notes = []chart.Annotation{}
+		}
+		c.JSON(http.StatusOK, notes)
+	}
+}
+
+// POST /api/annotations  {"symbol":"AAPL","time":"2025-01-02T14:35:00Z","price":187.2,"kind":"buy","label":"+100"}
+func AddAnnotation(opts Options) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var a chart.Annotation
+		if err := c.ShouldBindJSON(&a); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		kind, ok := chart.ParseAnnotationKind(string(a.Kind))
+		if !ok || a.Time.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "an annotation needs a time and a kind (buy, sell, earnings, dividend or note)"})
+			return
+		}
+		a.Kind, a.Symbol = kind, strings.ToUpper(a.Symbol)
+		if err := annot.Add(opts.Annotations, a); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, a)
+	}
+}
@@ -0,0 +1,165 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/drawings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// drawingRequest is the body of POST /api/drawings: JSON with from/to
+// anchors, or the index page's form with from_time, from_price, to_time
+// and to_price fields.
+type drawingRequest struct {
+	Symbol string `json:"symbol"`
+	chart.Drawing
+}
+
+// GET /api/drawings?symbol=AAPL
+func ListDrawings() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := strings.ToUpper(orDefault(c.Query("symbol"), "", "AAPL"))
+		store, err := drawings.Load("")
+		if err != nil {
+			drawingsError(c, http.StatusInternalServerError, err)
+			return
+		}
+		drawingsReply(c, http.StatusOK, symbol, store.For(symbol))
+	}
+}
+
+// POST /api/drawings  {"symbol":"AAPL","kind":"trend","from":{"time":"…","price":187},"to":{"time":"…","price":190}}
+func AddDrawing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		req, err := bindDrawing(c)
+		if err != nil {
+			drawingsError(c, http.StatusBadRequest, err)
+			return
+		}
+		ds, err := drawings.Update("", req.Symbol, func(ds []chart.Drawing) []chart.Drawing {
+			return append(ds, req.Drawing)
+		})
+		if err != nil {
+			drawingsError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.Header("HX-Trigger", "drawings-changed")
+		drawingsReply(c, http.StatusCreated, req.Symbol, ds)
+	}
+}
+
+// DELETE /api/drawings?symbol=AAPL&index=2  (every drawing of the symbol
+// when index is omitted)
+func DeleteDrawing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := strings.ToUpper(orDefault(c.Query("symbol"), "", "AAPL"))
+		index := -1
+		if s := c.Query("index"); s != "" {
+			var err error
+			if index, err = strconv.Atoi(s); err != nil || index < 0 {
+				drawingsError(c, http.StatusBadRequest, fmt.Errorf("bad index %q", s))
+				return
+			}
+		}
+		ds, err := drawings.Update("", symbol, func(ds []chart.Drawing) []chart.Drawing {
+			if index < 0 {
+				return nil
+			}
+			if index < len(ds) {
+				return slices.Delete(ds, index, index+1)
+			}
+			return ds
+		})
+		if err != nil {
+			drawingsError(c, http.StatusInternalServerError, err)
+			return
+		}
+		c.Header("HX-Trigger", "drawings-changed")
+		drawingsReply(c, http.StatusOK, symbol, ds)
+	}
+}
+
+func bindDrawing(c *gin.Context) (drawingRequest, error) {
+	var req drawingRequest
+	if c.ContentType() == "application/json" {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			return req, err
+		}
+	} else {
+		req.Symbol = c.PostForm("symbol")
+		req.Kind = chart.DrawingKind(c.PostForm("kind"))
+		var err error
+		if req.From, err = formAnchor(c, "from", req.Kind != chart.DrawLevel); err != nil {
+			return req, err
+		}
+		if req.Kind != chart.DrawLevel {
+			if req.To, err = formAnchor(c, "to", true); err != nil {
+				return req, err
+			}
+		}
+	}
+	req.Symbol = strings.ToUpper(strings.TrimSpace(req.Symbol))
+	if req.Symbol == "" {
+		return req, fmt.Errorf("missing symbol")
+	}
+	kind, ok := chart.ParseDrawingKind(string(req.Kind))
+	if !ok {
+		return req, fmt.Errorf("unknown drawing kind %q (want level, trend or fib)", req.Kind)
+	}
+	req.Kind = kind
+	return req, req.Validate()
+}
+
+// formAnchor reads the <name>_time and <name>_price form fields. Times
+// are local, as the chart's x axis shows them.
+func formAnchor(c *gin.Context, name string, needTime bool) (chart.Anchor, error) {
+	var a chart.Anchor
+	p, err := strconv.ParseFloat(strings.TrimSpace(c.PostForm(name+"_price")), 64)
+	if err != nil {
+		return a, fmt.Errorf("%s price: want a number", name)
+	}
+	a.Price = p
+	s := strings.TrimSpace(c.PostForm(name + "_time"))
+	if s == "" {
+		if needTime {
+			return a, fmt.Errorf("%s time: missing", name)
+		}
+		return a, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", time.RFC3339, "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			a.Time = t
+			return a, nil
+		}
+	}
+	return a, fmt.Errorf("%s time: want e.g. 2025-01-02 14:30", name)
+}
+
+// drawingsReply answers htmx with the drawings list fragment and anyone
+// else with JSON.
+func drawingsReply(c *gin.Context, status int, symbol string, ds []chart.Drawing) {
+	if c.GetHeader("HX-Request") != "" {
+		c.HTML(status, "drawings.html", gin.H{"symbol": symbol, "drawings": ds})
+		return
+	}
+	if ds == nil {
+		ds = []chart.Drawing{}
+	}
+	c.JSON(status, ds)
+}
+
+func drawingsError(c *gin.Context, status int, err error) {
+	if c.GetHeader("HX-Request") != "" {
+		// htmx only swaps 2xx responses; show the error in place
+		c.String(http.StatusOK, "error: %v", err)
+		return
+	}
+	c.JSON(status, gin.H{"error": err.Error()})
+}
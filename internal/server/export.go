@@ -0,0 +1,96 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/chart/studies"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fetchOHLC and fetchCompare get ChartImage's data; tests stub them.
+var (
+	fetchOHLC    = chart.FetchIntradayOHLC
+	fetchCompare = chart.FetchCompare
+)
+
+// GET /chart.png?symbol=MSFT&range=5d&interval=15m&view=candles&volume=1&studies=sma:20,ema:50&width=1200&height=600
+// GET /chart.svg?symbols=AAPL,MSFT,QQQ&range=1mo&interval=1d
+//
+// The same parameters as /chart, as a static image for embedding where
+// JavaScript doesn't run (chat messages, wikis).
+func ChartImage(opts Options, format string) gin.HandlerFunc {
+	contentType := "image/png"
+	if format == "svg" {
+		contentType = "image/svg+xml"
+	}
+	return func(c *gin.Context) {
+		symbol := orDefault(c.Query("symbol"), "", "AAPL")
+		rng := orDefault(c.Query("range"), "", "1d")
+		interval := orDefault(c.Query("interval"), "", "1m")
+		view, ok := chart.ParseViewMode(orDefault(c.Query("view"), "", "candles"))
+		if !ok {
+			c.String(http.StatusBadRequest, "error: unknown view %q", c.Query("view"))
+			return
+		}
+		scale, ok := chart.ParseScale(c.Query("scale"))
+		if !ok {
+			c.String(http.StatusBadRequest, "error: unknown scale %q", c.Query("scale"))
+			return
+		}
+		specs, err := studies.ParseSpecs(c.Query("studies"))
+		if err != nil {
+			c.String(http.StatusBadRequest, "error: %v", err)
+			return
+		}
+		volume, _ := strconv.ParseBool(orDefault(c.Query("volume"), "", "false"))
+
+		ic := chart.ImageChart{
+			View:    view,
+			Scale:   scale,
+			Width:   queryPixels(c, "width", 1200),
+			Height:  queryPixels(c, "height", 600),
+			Title:   fmt.Sprintf("%s  %s/%s", symbol, rng, interval),
+			Cursor:  -1,
+			Theme:   opts.Theme,
+			Volume:  volume,
+			Studies: specs,
+		}
+
+		var buf bytes.Buffer
+		if symbols := compareSymbols(c.Query("symbol"), c.Query("symbols")); len(symbols) > 1 {
+			var raw []chart.Series
+			if raw, err = fetchCompare(symbols, rng, interval); err != nil {
+				c.String(http.StatusBadRequest, "error: %v", err)
+				return
+			}
+			ic.Title = fmt.Sprintf("%s/%s", rng, interval)
+			err = ic.EncodeCompare(&buf, format, chart.CompareSeries(raw))
+		} else {
+			var ticks []chart.Tick
+			if ticks, err = fetchOHLC(symbol, rng, interval); err != nil {
+				c.String(http.StatusBadRequest, "error: %v", err)
+				return
+			}
+			err = ic.Encode(&buf, format, ticks)
+		}
+		if err != nil {
+			c.String(http.StatusInternalServerError, "render error: %v", err)
+			return
+		}
+		c.Data(http.StatusOK, contentType, buf.Bytes())
+	}
+}
+
+// queryPixels reads an image dimension, clamped to a sane range.
+func queryPixels(c *gin.Context, key string, fallback int) int {
+	n, err := strconv.Atoi(c.Query(key))
+	if err != nil {
+		return fallback
+	}
+	return min(max(n, 200), 4000)
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ticker-forge/internal/chart"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestChartImageStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t0 := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+	ticks := []chart.Tick{
+		{T: t0, O: 100, H: 101, L: 99, C: 100.5, V: 10},
+		{T: t0.Add(time.Minute), O: 100.5, H: 102, L: 100, C: 101.5, V: 12},
+	}
+	fetchOHLC = func(string, string, string) ([]chart.Tick, error) { return ticks, nil }
+	fetchCompare = func(symbols []string, _, _ string) ([]chart.Series, error) {
+		var out []chart.Series
+		for _, s := range symbols {
+			out = append(out, chart.Series{Name: s, Ticks: ticks})
+		}
+		return out, nil
+	}
+	t.Cleanup(func() { fetchOHLC, fetchCompare = chart.FetchIntradayOHLC, chart.FetchCompare })
+
+	cases := []struct {
+		name, format, query string
+		want                int
+	}{
+		{"png", "png", "symbol=AAPL", http.StatusOK},
+		{"svg compare", "svg", "symbols=AAPL,MSFT", http.StatusOK},
+		// an unknown format fails to encode
+		{"encode error", "gif", "symbol=AAPL", http.StatusInternalServerError},
+		{"compare encode error", "gif", "symbols=AAPL,MSFT", http.StatusInternalServerError},
+		{"bad view", "png", "view=pie", http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(w)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/chart?"+c.query, nil)
+			ChartImage(Options{}, c.format)(ctx)
+			if w.Code != c.want {
+				t.Errorf("status %d, want %d: %s", w.Code, c.want, w.Body.String())
+			}
+		})
+	}
+}
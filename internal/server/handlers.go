@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"html/template"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"ticker-forge/internal/chart"
 
@@ -18,6 +20,57 @@ func Index(opts Options) gin.HandlerFunc {
 			"symbol":   orDefault(c.Query("symbol"), opts.DefaultSymbol, "AAPL"),
 			"range":    orDefault(c.Query("range"), opts.DefaultRange, "1d"),
 			"interval": orDefault(c.Query("interval"), opts.DefaultInterval, "1m"),
+			"view":     orDefault(c.Query("view"), "", "candles"),
+			"scale":    orDefault(c.Query("scale"), "", "linear"),
+			"profile":  orDefault(c.Query("profile"), "", "none"),
+			"patterns": queryBool(c, "patterns"),
+			"pivots":   orDefault(c.Query("pivots"), "", "none"),
+			"zones":    queryBool(c, "zones"),
+			"symbols":  c.Query("symbols"),
+			"sparklines": sparklineSymbols(
+				orDefault(c.Query("symbol"), opts.DefaultSymbol, "AAPL"), c.Query("symbols")),
+		})
+	}
+}
+
+// sparklineSymbols lists the symbols the index page draws sparklines
+// for: the compared ones, or the one charted.
+func sparklineSymbols(symbol, symbols string) []string {
+	if list := compareSymbols(symbol, symbols); len(list) > 0 {
+		return list
+	}
+	return []string{strings.ToUpper(symbol)}
+}
+
+// GET /sparkline?symbol=MSFT&range=5d&interval=15m
+//
+// One symbol's closes as an inline SVG sparkline with the last price and
+// change, the fragment the index page loads for each symbol.
+func Sparkline() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		symbol := strings.ToUpper(orDefault(c.Query("symbol"), "", "AAPL"))
+		rng := orDefault(c.Query("range"), "", "1d")
+		interval := orDefault(c.Query("interval"), "", "1m")
+		ticks, err := fetchOHLC(symbol, rng, interval)
+		if err != nil {
+			c.String(http.StatusBadGateway, "error: %v", err)
+			return
+		}
+		if len(ticks) == 0 {
+			c.String(http.StatusNotFound, "error: no data for %s", symbol)
+			return
+		}
+		closes := make([]float64, len(ticks))
+		for i, k := range ticks {
+			closes[i] = k.C
+		}
+		last, change := closes[len(closes)-1], 0.0
+		if closes[0] != 0 {
+			change = (last/closes[0] - 1) * 100
+		}
+		c.HTML(http.StatusOK, "sparkline.html", gin.H{
+			"symbol": symbol, "range": rng, "interval": interval,
+			"closes": closes, "last": last, "change": change,
 		})
 	}
 }
@@ -28,35 +81,69 @@ func Frame() gin.HandlerFunc {
 		rng := orDefault(c.Query("range"), "", "1d")
 		interval := orDefault(c.Query("interval"), "", "1m")
 		view := orDefault(c.Query("view"), "", "candles")
+		scale := orDefault(c.Query("scale"), "", "linear")
+		profile := orDefault(c.Query("profile"), "", "none")
+		patterns, zones := onOff(queryBool(c, "patterns")), onOff(queryBool(c, "zones"))
+		pivots := orDefault(c.Query("pivots"), "", "none")
+		symbols := c.Query("symbols")
 
 		html := fmt.Sprintf(
-			`<iframe class="chart-frame" src="/chart?symbol=%s&range=%s&interval=%s&view=%s" loading="lazy"></iframe>`,
+			`<iframe class="chart-frame" src="/chart?symbol=%s&range=%s&interval=%s&view=%s&scale=%s&profile=%s&patterns=%s&pivots=%s&zones=%s&symbols=%s" loading="lazy"></iframe>`,
 			template.URLQueryEscaper(symbol),
 			template.URLQueryEscaper(rng),
 			template.URLQueryEscaper(interval),
 			template.URLQueryEscaper(view),
+			template.URLQueryEscaper(scale),
+			template.URLQueryEscaper(profile),
+			patterns,
+			template.URLQueryEscaper(pivots),
+			zones,
+			template.URLQueryEscaper(symbols),
 		)
 		c.Header("Content-Type", "text/html; charset=utf-8")
 		c.String(http.StatusOK, html)
 	}
 }
 
-// GET /chart?symbol=MSFT&range=1d&interval=1m&view=candles|line
-func Chart() gin.HandlerFunc {
+// GET /chart?symbol=MSFT&range=1d&interval=1m&view=candles|line|heikin-ashi|ohlc|renko|pnf|kagi&scale=linear|log|percent
+// GET /chart?symbol=MSFT&view=candles&profile=volume|tpo  (profile beside the candles)
+// GET /chart?symbol=MSFT&view=candles&patterns=on  (candlestick patterns marked)
+// GET /chart?symbol=MSFT&range=5d&pivots=classic|fib|camarilla&zones=on  (pivots, support/resistance)
+// GET /chart?symbols=AAPL,MSFT,QQQ&range=1mo&interval=1d  (comparison overlay)
+func Chart(opts Options) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		symbol := orDefault(c.Query("symbol"), "", "AAPL")
 		rng := orDefault(c.Query("range"), "", "1d")
 		interval := orDefault(c.Query("interval"), "", "1m")
-		view := strings.ToLower(orDefault(c.Query("view"), "", "candles"))
+		view, ok := chart.ParseViewMode(orDefault(c.Query("view"), "", "candles"))
+		if !ok {
+			c.String(http.StatusBadRequest, "error: unknown view %q", c.Query("view"))
+			return
+		}
+		scale, ok := chart.ParseScale(c.Query("scale"))
+		if !ok {
+			c.String(http.StatusBadRequest, "error: unknown scale %q", c.Query("scale"))
+			return
+		}
+		profile, ok := chart.ParseProfileMode(c.Query("profile"))
+		if !ok {
+			c.String(http.StatusBadRequest, "error: unknown profile %q", c.Query("profile"))
+			return
+		}
+		pivots, ok := chart.ParsePivotMethod(c.Query("pivots"))
+		if !ok {
+			c.String(http.StatusBadRequest, "error: unknown pivots %q", c.Query("pivots"))
+			return
+		}
+		zones := queryBool(c, "zones")
 
-		switch view {
-		case "line":
-			times, closes, err := chart.FetchIntraday(symbol, rng, interval)
+		if symbols := compareSymbols(c.Query("symbol"), c.Query("symbols")); len(symbols) > 1 {
+			raw, err := chart.FetchCompare(symbols, rng, interval)
 			if err != nil {
 				c.String(http.StatusBadRequest, "error: %v", err)
 				return
 			}
-			page, err := chart.RenderLinePage(symbol, times, closes)
+			page, err := chart.RenderComparePage(chart.CompareSeries(raw), scale)
 			if err != nil {
 				c.String(http.StatusInternalServerError, "render error: %v", err)
 				return
@@ -64,22 +151,102 @@ func Chart() gin.HandlerFunc {
 			c.Header("Content-Type", "text/html; charset=utf-8")
 			c.Writer.Write(page)
 			return
+		}
 
-		default: // "candles"
-			ticks, err := chart.FetchIntradayOHLC(symbol, rng, interval)
+		ov, err := overlay(opts, symbol)
+		if err != nil {
+			c.String(http.StatusInternalServerError, "overlay: %v", err)
+			return
+		}
+		ov.Profile = profile
+
+		if view == chart.ViewLine && pivots == chart.PivotNone && !zones {
+			times, closes, err := chart.FetchIntraday(symbol, rng, interval)
 			if err != nil {
 				c.String(http.StatusBadRequest, "error: %v", err)
 				return
 			}
-			page, err := chart.RenderKlinePage(symbol, ticks)
+			page, err := chart.RenderLinePage(symbol, times, closes, scale, ov)
 			if err != nil {
 				c.String(http.StatusInternalServerError, "render error: %v", err)
 				return
 			}
 			c.Header("Content-Type", "text/html; charset=utf-8")
 			c.Writer.Write(page)
+			return
+		}
+
+		ticks, err := chart.FetchIntradayOHLC(symbol, rng, interval)
+		if err != nil {
+			c.String(http.StatusBadRequest, "error: %v", err)
+			return
+		}
+		if queryBool(c, "patterns") {
+			ov.Patterns = chart.FindPatterns(ticks)
+		}
+		ov.Levels = chart.SessionPivots(pivots, ticks)
+		if zones {
+			ov.Zones = chart.SRZones(ticks, chart.MaxZones)
+		}
+		var page []byte
+		switch view {
+		case chart.ViewLine: // levels need the bars, not just closes
+			times, closes := make([]time.Time, len(ticks)), make([]float64, len(ticks))
+			for i, k := range ticks {
+				times[i], closes[i] = k.T, k.C
+			}
+			page, err = chart.RenderLinePage(symbol, times, closes, scale, ov)
+		case chart.ViewHeikinAshi:
+			page, err = chart.RenderHeikinAshiPage(symbol, ticks, scale, ov)
+		case chart.ViewOHLC:
+			page, err = chart.RenderOHLCPage(symbol, ticks, scale, ov)
+		case chart.ViewRenko:
+			page, err = chart.RenderRenkoPage(symbol, ticks, 0, scale)
+		case chart.ViewPointFigure:
+			page, err = chart.RenderPointFigurePage(symbol, ticks, 0, 3, scale)
+		case chart.ViewKagi:
+			page, err = chart.RenderKagiPage(symbol, ticks, 0, scale)
+		default: // candles
+			page, err = chart.RenderKlinePage(symbol, ticks, scale, ov)
 		}
+		if err != nil {
+			c.String(http.StatusInternalServerError, "render error: %v", err)
+			return
+		}
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Writer.Write(page)
+	}
+}
+
+// compareSymbols returns the symbols to overlay: the symbols= list, led by
+// symbol= when that is given and not already in the list.
+func compareSymbols(symbol, symbols string) []string {
+	list := chart.ParseSymbols(symbols)
+	if len(list) == 0 {
+		return nil
+	}
+	if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" && !slices.Contains(list, symbol) {
+		list = append([]string{symbol}, list...)
+	}
+	return list
+}
+
+// onOff spells b the way queryBool reads it back.
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// queryBool reports whether query parameter key is switched on, as an
+// HTML checkbox ("on") or by hand ("1", "true", "yes").
+func queryBool(c *gin.Context, key string) bool {
+	switch strings.ToLower(c.Query(key)) {
+	case "on", "1", "true", "yes":
+		return true
 	}
+	return false
 }
 
 func orDefault(val, preferred, fallback string) string {
@@ -2,8 +2,10 @@ package server
 
 import (
 	"html/template"
-	"log"
+	"log/slog"
 
+	"ticker-forge/internal/chart"
+	"ticker-forge/internal/theme"
 	"ticker-forge/internal/ui"
 
 	"github.com/gin-gonic/gin"
@@ -14,23 +16,41 @@ type Options struct {
 	DefaultSymbol  string
 	DefaultRange   string
 	DefaultInterval string
+	// Theme colors the chart pages; the zero value keeps theme.Default
+	Theme theme.Theme
+	// Annotations is the annotations file or API URL (see annot.Load)
+	Annotations string
 }
 
 func NewRouter(opts Options) *gin.Engine {
 	r := gin.Default()
 
+	if opts.Theme.Name != "" {
+		chart.UseWebTheme(opts.Theme)
+	}
+
 	// Static files (from embed)
 	r.StaticFS("/static", ui.StaticFS())
 
 	// Templates (from embed)
 	tfs := ui.TemplatesFS()
-	tpl := template.Must(template.New("").ParseFS(tfs, "*.html"))
+	tpl := template.Must(template.New("").Funcs(template.FuncMap{
+		"sparkline": chart.SparklineSVG,
+	}).ParseFS(tfs, "*.html"))
 	r.SetHTMLTemplate(tpl)
 
 	// Routes
 	r.GET("/", Index(opts))
 	r.GET("/frame", Frame())
-	r.GET("/chart", Chart())
+	r.GET("/sparkline", Sparkline())
+	r.GET("/chart", Chart(opts))
+	r.GET("/chart.png", ChartImage(opts, "png"))
+	r.GET("/chart.svg", ChartImage(opts, "svg"))
+	r.GET("/api/annotations", ListAnnotations(opts))
+	r.POST("/api/annotations", AddAnnotation(opts))
+	r.GET("/api/drawings", ListDrawings())
+	r.POST("/api/drawings", AddDrawing())
+	r.DELETE("/api/drawings", DeleteDrawing())
 
 	return r
 }
@@ -40,6 +60,6 @@ func ListenAndServe(opts Options) error {
 		opts.Port = "8080"
 	}
 	r := NewRouter(opts)
-	log.Printf("listening on http://localhost:%s", opts.Port)
+	slog.Info("listening", "addr", "http://localhost:"+opts.Port)
 	return r.Run(":" + opts.Port)
 }
@@ -0,0 +1,44 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ticker-forge/internal/chart"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSparkline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t0 := time.Date(2025, 1, 2, 14, 30, 0, 0, time.UTC)
+	fetchOHLC = func(string, string, string) ([]chart.Tick, error) {
+		return []chart.Tick{{T: t0, C: 100}, {T: t0.Add(time.Minute), C: 102}}, nil
+	}
+	t.Cleanup(func() { fetchOHLC = chart.FetchIntradayOHLC })
+	r := NewRouter(Options{})
+
+	cases := []struct {
+		path string
+		want []string
+	}{
+		{"/sparkline?symbol=msft", []string{"<svg", "MSFT", "102.00", "(&#43;2.00%)"}},
+		{"/?symbol=AAPL", []string{`hx-get="/sparkline?symbol=AAPL&range=1d`}},
+		{"/?symbol=AAPL&symbols=MSFT,GOOG", []string{"/sparkline?symbol=MSFT", "/sparkline?symbol=GOOG"}},
+	}
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status %d: %s", c.path, w.Code, w.Body.String())
+		}
+		for _, s := range c.want {
+			if !strings.Contains(w.Body.String(), s) {
+				t.Errorf("%s: body lacks %q", c.path, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package session keeps the TUI's state between runs in session.yaml
+// next to config.yaml: the open tabs, what each charts and how, and the
+// scroll position.
+package session
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"ticker-forge/internal/cfg"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tab is one chart tab. The names are those the chart package parses
+// (chart.ParseViewMode and friends).
+type Tab struct {
+	Symbols  []string `yaml:"symbols"`
+	Range    string   `yaml:"range"`
+	Interval string   `yaml:"interval"`
+	View     string   `yaml:"view,omitempty"`
+	Scale    string   `yaml:"scale,omitempty"`
+	Profile  string   `yaml:"profile,omitempty"`
+	Pivots   string   `yaml:"pivots,omitempty"`
+	Zones    bool     `yaml:"zones,omitempty"`
+	Patterns bool     `yaml:"patterns,omitempty"`
+	Volume   bool     `yaml:"volume,omitempty"`
+	// Refresh is the auto-refresh period ("30s"); empty is off.
+	Refresh string `yaml:"refresh,omitempty"`
+
+	// the viewport: bars shown (0 = as many as fit), bars scrolled back
+	// from the newest and the crosshair's bar (-1 = hidden)
+	Span   int `yaml:"span,omitempty"`
+	Offset int `yaml:"offset,omitempty"`
+	Cursor int `yaml:"cursor"`
+}
+
+// Session is the TUI's state when it last quit. The layout is not here:
+// it lives in config.yaml.
+type Session struct {
+	Tabs   []Tab  `yaml:"tabs"`
+	Active int    `yaml:"active,omitempty"`
+	Grid   bool   `yaml:"grid,omitempty"`
+	Focus  string `yaml:"focus,omitempty"`
+	// Studies are the studies pane's specs ("ema:50"); nil keeps the
+	// defaults, empty lists none.
+	Studies []string `yaml:"studies"`
+	// History holds the : command lines, oldest first.
+	History []string `yaml:"history,omitempty"`
+}
+
+// DefaultPath returns the location of session.yaml.
+func DefaultPath() (string, error) {
+	dir, err := cfg.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "session.yaml"), nil
+}
+
+// Load reads the session at path (DefaultPath when empty); a missing
+// file yields nil and no error.
+func Load(path string) (*Session, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return nil, err
+		}
+	}
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// Save writes s to path (DefaultPath when empty), creating its directory.
+func (s Session) Save(path string) error {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return err
+		}
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
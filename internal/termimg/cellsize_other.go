@@ -0,0 +1,9 @@
+//go:build !unix
+
+package termimg
+
+// CellSize returns the default 10×20 cell; there is no portable way to ask
+// the console for its font size here.
+func CellSize() (w, h int) {
+	return defaultCellW, defaultCellH
+}
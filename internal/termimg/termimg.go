@@ -0,0 +1,259 @@
+// Package termimg writes images to terminals that can display them, via
+// the Sixel or Kitty graphics protocols.
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// Protocol is an image protocol a terminal understands.
+type Protocol int
+
+const (
+	None Protocol = iota
+	Sixel
+	Kitty
+)
+
+var protocolNames = [...]string{"none", "sixel", "kitty"}
+
+func (p Protocol) String() string {
+	if p < 0 || int(p) >= len(protocolNames) {
+		return "none"
+	}
+	return protocolNames[p]
+}
+
+// ParseProtocol parses a --graphics value; "auto" (or "") runs Detect.
+func ParseProtocol(s string) (Protocol, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "auto" {
+		return Detect(), nil
+	}
+	for i, n := range protocolNames {
+		if s == n {
+			return Protocol(i), nil
+		}
+	}
+	return None, fmt.Errorf("unknown graphics protocol %q (want auto, sixel, kitty or none)", s)
+}
+
+// Detect guesses the protocol from the environment. Terminal
+// multiplexers get None: they drop or mangle image escapes unless
+// passthrough is configured, and there is no way to tell from here.
+func Detect() Protocol {
+	term := os.Getenv("TERM")
+	prog := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		return None
+	case os.Getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty",
+		term == "xterm-ghostty" || prog == "ghostty",
+		prog == "WezTerm":
+		return Kitty
+	case strings.HasPrefix(term, "foot"),
+		strings.Contains(term, "mlterm"),
+		strings.Contains(term, "contour"),
+		strings.Contains(term, "sixel"),
+		prog == "iTerm.app":
+		return Sixel
+	}
+	return None
+}
+
+// Cell size assumed when the terminal doesn't report one.
+const (
+	defaultCellW = 10
+	defaultCellH = 20
+)
+
+// Encode returns the escape sequence that draws img at the cursor, cols×rows
+// cells in size (used by Kitty to scale; Sixel draws pixels 1:1).
+func Encode(p Protocol, img image.Image, cols, rows int) (string, error) {
+	switch p {
+	case Sixel:
+		return EncodeSixel(img), nil
+	case Kitty:
+		return EncodeKitty(img, cols, rows)
+	}
+	return "", nil
+}
+
+// Block lays seq out as rows+1 lines of text for a line-based renderer
+// such as Bubble Tea's: rows blank lines reserve the cells and the last
+// line jumps back up to draw the image, so clearing each line as it is
+// painted never erases the picture. The cursor is left where it was.
+func Block(seq string, rows int) string {
+	if rows < 1 || seq == "" {
+		return ""
+	}
+	return strings.Repeat("\n", rows) +
+		"\x1b7" + fmt.Sprintf("\x1b[%dA\r", rows) + seq + "\x1b8"
+}
+
+// Clear returns the sequence that removes images drawn with p from the
+// screen. Sixel pixels go away when text is painted over them, so only
+// Kitty needs one.
+func Clear(p Protocol) string {
+	if p == Kitty {
+		return kittyDelete
+	}
+	return ""
+}
+
+// EncodeSixel encodes img as a DEC Sixel sequence. Images with more than
+// 256 colors are dithered to the web-safe palette.
+func EncodeSixel(img image.Image) string {
+	pm := paletted(img)
+	b := pm.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	var out strings.Builder
+	// P2=1: pixels we don't paint keep the terminal background
+	out.WriteString("\x1bP0;1;0q")
+	fmt.Fprintf(&out, "\"1;1;%d;%d", w, h)
+	for i, c := range pm.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&out, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	used := make([]bool, len(pm.Palette))
+	row := make([]byte, w)
+	for y0 := 0; y0 < h; y0 += 6 {
+		// which colors appear in this band of six rows
+		for i := range used {
+			used[i] = false
+		}
+		for y := y0; y < min(y0+6, h); y++ {
+			for x := 0; x < w; x++ {
+				used[pm.Pix[y*pm.Stride+x]] = true
+			}
+		}
+		first := true
+		for ci, ok := range used {
+			if !ok {
+				continue
+			}
+			if !first {
+				out.WriteByte('$') // back to the start of the band
+			}
+			first = false
+			for x := 0; x < w; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && y0+dy < h; dy++ {
+					if int(pm.Pix[(y0+dy)*pm.Stride+x]) == ci {
+						bits |= 1 << dy
+					}
+				}
+				row[x] = '?' + bits
+			}
+			fmt.Fprintf(&out, "#%d", ci)
+			writeRuns(&out, row)
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\")
+	return out.String()
+}
+
+// writeRuns writes sixel characters with run-length compression.
+func writeRuns(out *strings.Builder, row []byte) {
+	for i := 0; i < len(row); {
+		j := i
+		for j < len(row) && row[j] == row[i] {
+			j++
+		}
+		if n := j - i; n > 3 {
+			fmt.Fprintf(out, "!%d%c", n, row[i])
+		} else {
+			out.Write(row[i:j])
+		}
+		i = j
+	}
+}
+
+// paletted converts img to at most 256 colors, exactly when it already
+// has that few (as charts do).
+func paletted(img image.Image) *image.Paletted {
+	b := img.Bounds()
+	index := map[color.RGBA]uint8{}
+	var pal color.Palette
+	exact := true
+	for y := b.Min.Y; y < b.Max.Y && exact; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			if _, ok := index[c]; ok {
+				continue
+			}
+			if len(pal) == 256 {
+				exact = false
+				break
+			}
+			index[c] = uint8(len(pal))
+			pal = append(pal, c)
+		}
+	}
+	if !exact {
+		pm := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.WebSafe)
+		draw.FloydSteinberg.Draw(pm, pm.Rect, img, b.Min)
+		return pm
+	}
+	pm := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), pal)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			pm.Pix[(y-b.Min.Y)*pm.Stride+(x-b.Min.X)] = index[c]
+		}
+	}
+	return pm
+}
+
+const (
+	// kittyChunk is the largest base64 payload per Kitty escape.
+	kittyChunk = 4096
+	// kittyDelete deletes every image placement and frees its data.
+	kittyDelete = "\x1b_Ga=d,d=A,q=2\x1b\\"
+)
+
+// EncodeKitty encodes img as PNG for the Kitty graphics protocol, scaled
+// to cols×rows cells (0 keeps the pixel size). The previous images are
+// deleted first so a redraw replaces rather than stacks, the cursor
+// doesn't move (C=1) and the terminal sends no replies (q=2), which
+// would otherwise arrive as keystrokes.
+func EncodeKitty(img image.Image, cols, rows int) (string, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return "", err
+	}
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	var out strings.Builder
+	out.WriteString(kittyDelete)
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(len(data), kittyChunk)]
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		out.WriteString("\x1b_G")
+		if first {
+			out.WriteString("a=T,f=100,q=2,C=1")
+			if cols > 0 && rows > 0 {
+				fmt.Fprintf(&out, ",c=%d,r=%d", cols, rows)
+			}
+			out.WriteByte(',')
+		}
+		fmt.Fprintf(&out, "m=%d;%s\x1b\\", more, chunk)
+	}
+	return out.String(), nil
+}
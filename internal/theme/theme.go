@@ -0,0 +1,216 @@
+// Package theme holds the color palettes shared by the ASCII renderers, the
+// TUI styles and the web charts, and resolves them against the terminal's
+// color depth.
+package theme
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+)
+
+// Theme is a named set of colors. Colors are hex ("#26a69a") or ANSI
+// indexes ("2", "244"); empty fields inherit from Base when the theme is
+// user-defined.
+type Theme struct {
+	Name   string   `yaml:"-"`
+	Base   string   `yaml:"base,omitempty"`
+	Up     string   `yaml:"up,omitempty"`     // rising bars / yang lines
+	Down   string   `yaml:"down,omitempty"`   // falling bars / yin lines
+	Accent string   `yaml:"accent,omitempty"` // titles, prompt cursor
+	Subtle string   `yaml:"subtle,omitempty"` // hints, axis text
+	Error  string   `yaml:"error,omitempty"`
+	Series []string `yaml:"series,omitempty"` // compare lines, in order
+
+	// Background fills image output (Sixel/Kitty, PNG); character-cell
+	// charts keep the terminal's own background.
+	Background string `yaml:"background,omitempty"`
+}
+
+var builtins = map[string]Theme{
+	"dark": {
+		Up: "#26a69a", Down: "#ef5350", Accent: "#ff5faf", Subtle: "244", Error: "#ff5f5f",
+		Series:     []string{"#26a69a", "#42a5f5", "#ffca28", "#ab47bc", "#ff7043", "#8d6e63"},
+		Background: "#131722",
+	},
+	"light": {
+		Up: "#15803d", Down: "#b91c1c", Accent: "#7c3aed", Subtle: "#6b7280", Error: "#dc2626",
+		Series:     []string{"#15803d", "#1d4ed8", "#b45309", "#7e22ce", "#be123c", "#0f766e"},
+		Background: "#ffffff",
+	},
+	"high-contrast": {
+		Up: "#00ff00", Down: "#ff0000", Accent: "#ffff00", Subtle: "#ffffff", Error: "#ff0000",
+		Series:     []string{"#00ff00", "#00ffff", "#ffff00", "#ff00ff", "#ffffff", "#ff8000"},
+		Background: "#000000",
+	},
+	// Okabe–Ito colors: distinguishable with the common color-vision deficiencies.
+	"colorblind": {
+		Up: "#0072b2", Down: "#e69f00", Accent: "#cc79a7", Subtle: "244", Error: "#d55e00",
+		Series:     []string{"#0072b2", "#e69f00", "#009e73", "#cc79a7", "#56b4e9", "#f0e442"},
+		Background: "#1c1c1c",
+	},
+}
+
+// Default is the theme used when none is configured.
+const Default = "dark"
+
+// Names lists the built-in themes.
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for n := range builtins {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup resolves name, in any case, against the user-defined themes
+// first, then the built-ins. A user theme fills its blank fields from its
+// Base (or Default).
+func Lookup(name string, custom map[string]Theme) (Theme, error) {
+	if name == "" {
+		name = Default
+	}
+	name = strings.ToLower(name)
+	for key, t := range custom {
+		if !strings.EqualFold(key, name) {
+			continue
+		}
+		base, ok := builtins[strings.ToLower(orDefault(t.Base, Default))]
+		if !ok {
+			return Theme{}, fmt.Errorf("theme %q: unknown base %q", name, t.Base)
+		}
+		t = t.over(base)
+		t.Name = name
+		return t, t.validate()
+	}
+	t, ok := builtins[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (built-in: %s)", name, strings.Join(Names(), ", "))
+	}
+	t.Name = name
+	return t, nil
+}
+
+// MustLookup is Lookup for built-in names; it panics on a typo.
+func MustLookup(name string) Theme {
+	t, err := Lookup(name, nil)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// over fills t's blank fields from base.
+func (t Theme) over(base Theme) Theme {
+	t.Up = orDefault(t.Up, base.Up)
+	t.Down = orDefault(t.Down, base.Down)
+	t.Accent = orDefault(t.Accent, base.Accent)
+	t.Subtle = orDefault(t.Subtle, base.Subtle)
+	t.Error = orDefault(t.Error, base.Error)
+	t.Background = orDefault(t.Background, base.Background)
+	if len(t.Series) == 0 {
+		t.Series = base.Series
+	}
+	return t
+}
+
+func (t Theme) validate() error {
+	colors := append([]string{t.Up, t.Down, t.Accent, t.Subtle, t.Error, t.Background}, t.Series...)
+	for _, c := range colors {
+		if !validColor(c) {
+			return fmt.Errorf("theme %q: invalid color %q (want #rrggbb or 0-255)", t.Name, c)
+		}
+	}
+	return nil
+}
+
+func validColor(c string) bool {
+	if strings.HasPrefix(c, "#") {
+		_, err := colorful.Hex(c)
+		return err == nil
+	}
+	i, err := strconv.Atoi(c)
+	return err == nil && i >= 0 && i <= 255
+}
+
+// Detect returns the color depth of stdout, honoring NO_COLOR and
+// CLICOLOR/CLICOLOR_FORCE.
+func Detect() termenv.Profile {
+	return termenv.NewOutput(os.Stdout).EnvColorProfile()
+}
+
+// Palette is a Theme resolved for one terminal color profile: ready-made
+// escape sequences, all empty when the profile has no color.
+type Palette struct {
+	Up     string
+	Down   string
+	Cursor string // dim guide for the crosshair
+	Accent string // user drawings
+	Reset  string
+	Series []string
+}
+
+// Palette resolves t for profile p (see Detect).
+func (t Theme) Palette(p termenv.Profile) Palette {
+	seq := func(c string) string {
+		col := p.Color(c)
+		if col == nil {
+			return ""
+		}
+		if s := col.Sequence(false); s != "" {
+			return termenv.CSI + s + "m"
+		}
+		return ""
+	}
+	pal := Palette{Up: seq(t.Up), Down: seq(t.Down), Accent: seq(t.Accent)}
+	if p != termenv.Ascii {
+		pal.Cursor = termenv.CSI + termenv.FaintSeq + "m"
+		pal.Reset = termenv.CSI + termenv.ResetSeq + "m"
+	}
+	for _, c := range t.Series {
+		pal.Series = append(pal.Series, seq(c))
+	}
+	return pal
+}
+
+// CSS converts a theme color to "#rrggbb" for the web pages.
+func CSS(c string) string {
+	col := termenv.TrueColor.Color(c)
+	if col == nil {
+		return ""
+	}
+	return termenv.ConvertToRGB(col).Hex()
+}
+
+// RGBA converts a theme color for the image renderers.
+func RGBA(c string) color.RGBA {
+	col := termenv.TrueColor.Color(c)
+	if col == nil {
+		return color.RGBA{A: 0xff}
+	}
+	r, g, b := termenv.ConvertToRGB(col).RGB255()
+	return color.RGBA{R: r, G: g, B: b, A: 0xff}
+}
+
+// CSSSeries is Series converted with CSS.
+func (t Theme) CSSSeries() []string {
+	out := make([]string, len(t.Series))
+	for i, c := range t.Series {
+		out[i] = CSS(c)
+	}
+	return out
+}
+
+func orDefault(v, fallback string) string {
+	if v != "" {
+		return v
+	}
+	return fallback
+}
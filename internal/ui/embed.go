@@ -2,7 +2,6 @@ package ui
 
 import (
 	"embed"
-	"fmt"
 	"io/fs"
 	"net/http"
 )
@@ -11,21 +10,11 @@ import (
 var assets embed.FS
 
 func TemplatesFS() fs.FS {
-    sub, err := fs.Sub(assets, "server/templates")
-    if err != nil {
-        panic(err) // Handle error properly in production
-    }
-
-    fs.WalkDir(sub, ".", func(path string, d fs.DirEntry, err error) error {
-        if err != nil {
-            fmt.Println("Error:", err)
-            return err
-        }
-        fmt.Println("Found file:", path)
-        return nil
-    })
-
-    return sub
+	sub, err := fs.Sub(assets, "server/templates")
+	if err != nil {
+		panic(err) // Handle error properly in production
+	}
+	return sub
 }
 
 func StaticFS() http.FileSystem {
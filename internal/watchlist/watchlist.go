@@ -0,0 +1,187 @@
+// Package watchlist keeps the user's named watchlists in watchlists.yaml
+// next to config.yaml.
+package watchlist
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"ticker-forge/internal/cfg"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultName names the list a new Store starts with.
+const DefaultName = "default"
+
+// List is a named, ordered list of upper-case symbols.
+type List struct {
+	Name    string   `yaml:"name"`
+	Symbols []string `yaml:"symbols"`
+}
+
+// Store holds every watchlist and which one is shown.
+type Store struct {
+	Current string `yaml:"current,omitempty"`
+	Lists   []List `yaml:"lists"`
+}
+
+// DefaultPath returns the location of watchlists.yaml.
+func DefaultPath() (string, error) {
+	dir, err := cfg.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "watchlists.yaml"), nil
+}
+
+// Load reads the store at path (DefaultPath when empty); a missing file
+// yields an empty Store.
+func Load(path string) (Store, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return Store{}, err
+		}
+	}
+	var s Store
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes s to path (DefaultPath when empty), creating its directory.
+func (s Store) Save(path string) error {
+	if path == "" {
+		var err error
+		if path, err = DefaultPath(); err != nil {
+			return err
+		}
+	}
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Clone returns a deep copy of s, e.g. to save while s keeps changing.
+func (s Store) Clone() Store {
+	c := Store{Current: s.Current, Lists: make([]List, len(s.Lists))}
+	for i, l := range s.Lists {
+		c.Lists[i] = List{Name: l.Name, Symbols: slices.Clone(l.Symbols)}
+	}
+	return c
+}
+
+// Active returns the shown list, creating a DefaultName list holding
+// seed when the store has none and falling back to the first list when
+// Current names none of them.
+func (s *Store) Active(seed ...string) *List {
+	if len(s.Lists) == 0 {
+		s.Lists = []List{{Name: DefaultName, Symbols: normalize(seed)}}
+	}
+	i := s.index(s.Current)
+	if i < 0 {
+		i = 0
+	}
+	s.Current = s.Lists[i].Name
+	return &s.Lists[i]
+}
+
+func (s Store) index(name string) int {
+	return slices.IndexFunc(s.Lists, func(l List) bool { return strings.EqualFold(l.Name, name) })
+}
+
+// Switch shows the list delta places after the current one, wrapping.
+func (s *Store) Switch(delta int) *List {
+	s.Active()
+	n := len(s.Lists)
+	i := ((s.index(s.Current)+delta)%n + n) % n
+	s.Current = s.Lists[i].Name
+	return &s.Lists[i]
+}
+
+// Create adds an empty list called name and shows it; an existing list
+// of that name is shown instead.
+func (s *Store) Create(name string) (*List, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("watchlist name is empty")
+	}
+	if s.index(name) < 0 {
+		s.Lists = append(s.Lists, List{Name: name})
+	}
+	s.Current = name
+	return s.Active(), nil
+}
+
+// Delete removes the current list and shows the one before it; the last
+// list left can't be deleted.
+func (s *Store) Delete() error {
+	s.Active()
+	if len(s.Lists) == 1 {
+		return errors.New("can't delete the only watchlist")
+	}
+	i := s.index(s.Current)
+	s.Lists = slices.Delete(s.Lists, i, i+1)
+	s.Current = s.Lists[max(i-1, 0)].Name
+	return nil
+}
+
+// Add appends the symbols the list doesn't hold yet and returns how many
+// it added.
+func (l *List) Add(symbols ...string) int {
+	n := 0
+	for _, sym := range normalize(symbols) {
+		if !slices.Contains(l.Symbols, sym) {
+			l.Symbols = append(l.Symbols, sym)
+			n++
+		}
+	}
+	return n
+}
+
+// Remove drops the symbol at i.
+func (l *List) Remove(i int) {
+	if i >= 0 && i < len(l.Symbols) {
+		l.Symbols = slices.Delete(l.Symbols, i, i+1)
+	}
+}
+
+// Move swaps the symbol at i with its neighbour delta places away and
+// returns its new index; it stays put at either end.
+func (l *List) Move(i, delta int) int {
+	j := i + delta
+	if i < 0 || i >= len(l.Symbols) || j < 0 || j >= len(l.Symbols) {
+		return i
+	}
+	l.Symbols[i], l.Symbols[j] = l.Symbols[j], l.Symbols[i]
+	return j
+}
+
+func normalize(symbols []string) []string {
+	var out []string
+	for _, s := range symbols {
+		if s = strings.ToUpper(strings.TrimSpace(s)); s != "" && !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
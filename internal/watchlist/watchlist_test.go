@@ -0,0 +1,92 @@
+package watchlist
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestStoreLists(t *testing.T) {
+	var s Store
+	if l := s.Active("aapl", " msft ", "AAPL", ""); l.Name != DefaultName || !slices.Equal(l.Symbols, []string{"AAPL", "MSFT"}) {
+		t.Fatalf("Active seeded %+v", *l)
+	}
+	if _, err := s.Create("  "); err == nil {
+		t.Error("created a list with a blank name")
+	}
+	if l, _ := s.Create("Tech"); l.Name != "Tech" || s.Current != "Tech" {
+		t.Errorf("Create shows %q, current %q", l.Name, s.Current)
+	}
+	if l, _ := s.Create("tech"); len(s.Lists) != 2 || l.Name != "Tech" {
+		t.Errorf("creating an existing name made %d lists, showing %q", len(s.Lists), l.Name)
+	}
+
+	cases := []struct {
+		delta int
+		want  string
+	}{{1, DefaultName}, {1, "Tech"}, {-1, DefaultName}, {-3, "Tech"}}
+	for _, c := range cases {
+		if got := s.Switch(c.delta).Name; got != c.want {
+			t.Errorf("Switch(%d) = %q, want %q", c.delta, got, c.want)
+		}
+	}
+
+	if err := s.Delete(); err != nil || s.Current != DefaultName {
+		t.Errorf("Delete: %v, current %q", err, s.Current)
+	}
+	if err := s.Delete(); err == nil {
+		t.Error("deleted the only list")
+	}
+	s.Current = "gone"
+	if l := s.Active(); l.Name != DefaultName {
+		t.Errorf("Active with an unknown current = %q", l.Name)
+	}
+}
+
+func TestListEdits(t *testing.T) {
+	l := List{Symbols: []string{"AAPL"}}
+	if n := l.Add("msft", "aapl", "GOOG", "msft"); n != 2 {
+		t.Errorf("Add added %d, want 2", n)
+	}
+	cases := []struct {
+		name     string
+		edit     func(*List) int
+		wantIdx  int
+		wantSyms []string
+	}{
+		{"move down", func(l *List) int { return l.Move(0, 1) }, 1, []string{"MSFT", "AAPL", "GOOG"}},
+		{"move past the end", func(l *List) int { return l.Move(2, 1) }, 2, []string{"MSFT", "AAPL", "GOOG"}},
+		{"move up", func(l *List) int { return l.Move(2, -1) }, 1, []string{"MSFT", "GOOG", "AAPL"}},
+		{"remove out of range", func(l *List) int { l.Remove(5); return 5 }, 5, []string{"MSFT", "GOOG", "AAPL"}},
+		{"remove", func(l *List) int { l.Remove(0); return 0 }, 0, []string{"GOOG", "AAPL"}},
+	}
+	for _, c := range cases {
+		if i := c.edit(&l); i != c.wantIdx || !slices.Equal(l.Symbols, c.wantSyms) {
+			t.Errorf("%s: index %d, symbols %v; want %d, %v", c.name, i, l.Symbols, c.wantIdx, c.wantSyms)
+		}
+	}
+}
+
+func TestStoreSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "watchlists.yaml")
+	if s, err := Load(path); err != nil || len(s.Lists) != 0 {
+		t.Fatalf("Load of a missing file = %+v, %v", s, err)
+	}
+	want := Store{Current: "tech", Lists: []List{
+		{Name: DefaultName, Symbols: []string{"AAPL"}},
+		{Name: "tech", Symbols: []string{"MSFT", "NVDA"}},
+	}}
+	saved := want.Clone()
+	if err := saved.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	saved.Lists[1].Symbols[0] = "IBM" // a clone shares nothing with want
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip: got %+v, want %+v", got, want)
+	}
+}